@@ -1,3 +1,7 @@
 #!/bin/bash
 
-go build -o teel -ldflags="-X main.version=$(git describe --always --long)" cmd/teel/main.go
+ldflags="-X main.version=$(git describe --always --long --dirty)"
+ldflags+=" -X main.commit=$(git rev-parse HEAD)"
+ldflags+=" -X main.date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+
+go build -o teel -ldflags="$ldflags" cmd/teel/main.go
@@ -1,33 +1,133 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"github.com/sebuckler/teel/internal/cmdbuilder"
 	"github.com/sebuckler/teel/internal/executor"
 	"github.com/sebuckler/teel/internal/fs"
 	"github.com/sebuckler/teel/internal/logger"
+	"github.com/sebuckler/teel/internal/middleware"
 	"github.com/sebuckler/teel/internal/scaffolder"
 	"github.com/sebuckler/teel/internal/scaffolder/directives"
+	"github.com/sebuckler/teel/internal/services"
+	"github.com/sebuckler/teel/internal/site"
 	"github.com/sebuckler/teel/pkg/cli"
 	"os"
+	"time"
 )
 
-var version string
+const (
+	gracePeriodEnv = "TEEL_GRACE_PERIOD"
+	logFileEnv     = "TEEL_LOG_FILE"
+)
+
+var (
+	version string
+	commit  string
+	date    string
+)
 
 func main() {
-	file, fileErr := fs.OpenBufferedFileWriter("teel.log")
+	fileSystem := fs.NewOSFileSystem()
+	paths, pathsErr := site.ResolvePaths(fileSystem, ".", "")
+
+	if pathsErr != nil {
+		executor.Exit(pathsErr)
+	}
+
+	gracePeriod, graceErr := getGracePeriod()
 
-	if fileErr != nil {
-		executor.Exit(fileErr)
+	if graceErr != nil {
+		executor.Exit(graceErr)
 	}
 
-	fileLogger := logger.New(file, nil)
-	siteScaffolder := scaffolder.New(directives.NewConfig())
-	cmdBuilder := cmdbuilder.New(fileLogger, siteScaffolder)
+	options := &cmdbuilder.Options{Log: &logger.Config{}}
+	execConfig := &executor.Config{FileSystem: fileSystem, GracePeriod: gracePeriod}
+	appLogger := logger.New(logger.NewConsoleSink(os.Stderr, logger.DefaultLevel, logger.NewTextFormatter()))
+	siteScaffolder := scaffolder.New(fileSystem, directives.NewConfig(fileSystem))
+	siteServices := services.New(fileSystem, appLogger, siteScaffolder, paths)
+	cmdBuilder := cmdbuilder.New(options)
 	parser := cli.NewParser(cli.GNU, cmdBuilder.Build())
+	parser.EnablePluginsFunc(func() []string {
+		applyStateDir(paths, options)
+
+		return []string{paths.PluginDir()}
+	})
 	runner := cli.NewRunner(parser, version, os.Stdout)
-	cmdExecutor := executor.New(fileLogger, runner)
+	runner.AddParseHook(
+		configureState(paths, options, execConfig),
+		configureLogger(appLogger, options.Log),
+	)
+	runner.AddMiddleware(
+		services.Middleware(siteServices),
+		middleware.Logging(appLogger),
+	)
+	runner.SetExecutionMode(cli.LeafOnly)
+	versionInfo := cli.NewVersionInfo(version, commit, date)
+	runner.SetVersionInfo(versionInfo)
+	runner.SetVersionTemplate(`{{.Name}} {{.Version}}{{if .Dirty}} (modified){{end}}
+{{if .Commit}}commit: {{.Commit}}
+{{end}}{{if .BuildDate}}built:  {{.BuildDate}}
+{{end}}go:     {{.GoVersion}}
+`)
+	execConfig.VersionInfo = versionInfo
+	cmdExecutor := executor.New(appLogger, runner, execConfig)
 	execErr := cmdExecutor.Execute()
-	_ = file.Close()
+	_ = appLogger.Close()
 
 	executor.Exit(execErr)
 }
+
+func applyStateDir(p *site.Paths, o *cmdbuilder.Options) {
+	if o.StateDir != "" {
+		p.State = o.StateDir
+	}
+}
+
+func getGracePeriod() (time.Duration, error) {
+	value := os.Getenv(gracePeriodEnv)
+
+	if value == "" {
+		return executor.DefaultGracePeriod, nil
+	}
+
+	gracePeriod, parseErr := time.ParseDuration(value)
+
+	if parseErr != nil || gracePeriod <= 0 {
+		return 0, errors.New("invalid " + gracePeriodEnv + ": " + value + " is not a positive duration such as 30s")
+	}
+
+	return gracePeriod, nil
+}
+
+func configureLogger(l logger.Logger, c *logger.Config) cli.ParseHook {
+	return func(ctx context.Context) error {
+		l.Redact(cli.SecretValues(ctx)...)
+		sinks, sinksErr := c.NewSinks(os.Stderr)
+
+		if sinksErr != nil {
+			return sinksErr
+		}
+
+		return l.SetSinks(sinks...)
+	}
+}
+
+func configureState(p *site.Paths, o *cmdbuilder.Options, e *executor.Config) cli.ParseHook {
+	return func(ctx context.Context) error {
+		applyStateDir(p, o)
+
+		if o.Log.File == "" {
+			o.Log.File = os.Getenv(logFileEnv)
+		}
+
+		if o.Log.File == "" {
+			o.Log.File = p.LogFile()
+		}
+
+		e.CrashDir = p.CrashDir()
+
+		return nil
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"github.com/sebuckler/teel/internal/logger"
-	"github.com/sebuckler/teel/internal/scaffolder"
 	"github.com/sebuckler/teel/pkg/cli"
 )
 
@@ -13,21 +12,41 @@ type CommandBuilder interface {
 }
 
 type commandBuilder struct {
-	logger     logger.Logger
-	scaffolder scaffolder.Scaffolder
+	options *Options
 }
 
-func New(l logger.Logger, s scaffolder.Scaffolder) CommandBuilder {
+type commandFactory func() cli.CommandBuilder
+
+var commandFactories []commandFactory
+
+type Options struct {
+	Log      *logger.Config
+	StateDir string
+}
+
+func New(o *Options) CommandBuilder {
+	if o == nil {
+		o = &Options{}
+	}
+
+	if o.Log == nil {
+		o.Log = &logger.Config{}
+	}
+
 	return &commandBuilder{
-		logger:     l,
-		scaffolder: s,
+		options: o,
 	}
 }
 
+func registerCommand(f commandFactory) {
+	commandFactories = append(commandFactories, f)
+}
+
 func (c *commandBuilder) Build() cli.CommandBuilder {
 	var a bool
 	var b string
 	rootCmd := cli.NewCommand("teel", context.Background())
+	rootCmd.AddUsageText("manage static sites")
 	rootCmd.AddBoolArg(&a, &cli.ArgDefinition{
 		Name:      "a",
 		ShortName: 'a',
@@ -36,20 +55,86 @@ func (c *commandBuilder) Build() cli.CommandBuilder {
 		Name:      "b",
 		ShortName: 'b',
 	})
-	rootCmd.AddRunFunc(func(ctx context.Context, o []string) {
-		fmt.Print("a: ", a, ", b: ", b, "\n")
-		fmt.Println("welcome to the thunderdome")
+	rootCmd.AddStringArg(&c.options.Log.File, &cli.ArgDefinition{
+		Name:      "log-file",
+		UsageText: "write logs to the given file",
+		Required:  true,
+	})
+	rootCmd.AddStringArg(&c.options.Log.FileFormat, &cli.ArgDefinition{
+		Name:      "log-file-format",
+		UsageText: "format of log messages written to the log file",
+		Required:  true,
+		Choices:   logger.FormatNames(),
+	})
+	rootCmd.AddStringArg(&c.options.Log.FileLevel, &cli.ArgDefinition{
+		Name:      "log-file-level",
+		UsageText: "minimum level of log messages written to the log file",
+		Required:  true,
+		Choices:   logger.LevelNames(),
 	})
-	subCmd := cli.NewCommand("subby", context.Background())
-	file := "filename"
-	subCmd.AddStringArg(&file, &cli.ArgDefinition{
-		Name:      "file",
-		ShortName: 'f',
+	rootCmd.AddStringArg(&c.options.Log.FlushInterval, &cli.ArgDefinition{
+		Name:      "log-flush-interval",
+		UsageText: "how often buffered log messages are written to the log file, e.g. 500ms",
+		Required:  true,
 	})
-	subCmd.AddRunFunc(func(ctx context.Context, o []string) {
-		fmt.Println("and me, " + file + "!")
+	rootCmd.AddIntArg(&c.options.Log.FlushSize, &cli.ArgDefinition{
+		Name:      "log-flush-size",
+		UsageText: "buffered bytes at which log messages are written to the log file",
+		Required:  true,
 	})
-	rootCmd.AddSubcommand(subCmd)
+	rootCmd.AddStringArg(&c.options.Log.Format, &cli.ArgDefinition{
+		Name:      "log-format",
+		UsageText: "format of log messages written to the console",
+		Required:  true,
+		Choices:   logger.FormatNames(),
+	})
+	rootCmd.AddStringArg(&c.options.Log.Level, &cli.ArgDefinition{
+		Name:      "log-level",
+		UsageText: "minimum level of log messages written to the console",
+		Required:  true,
+		Choices:   logger.LevelNames(),
+	})
+	rootCmd.AddIntArg(&c.options.Log.MaxAge, &cli.ArgDefinition{
+		Name:      "log-max-age",
+		UsageText: "days to keep rotated log files",
+		Required:  true,
+	})
+	rootCmd.AddIntArg(&c.options.Log.MaxBackups, &cli.ArgDefinition{
+		Name:      "log-max-backups",
+		UsageText: "number of rotated log files to keep",
+		Required:  true,
+	})
+	rootCmd.AddIntArg(&c.options.Log.MaxSize, &cli.ArgDefinition{
+		Name:      "log-max-size",
+		UsageText: "size in megabytes at which the log file is rotated",
+		Required:  true,
+	})
+	rootCmd.AddBoolArg(&c.options.Log.NoCompress, &cli.ArgDefinition{
+		Name:      "log-no-compress",
+		UsageText: "do not gzip rotated log files",
+	})
+	rootCmd.AddBoolArg(&c.options.Log.Quiet, &cli.ArgDefinition{
+		Name:      "quiet",
+		ShortName: 'q',
+		UsageText: "only log errors",
+	})
+	rootCmd.AddBoolArg(&c.options.Log.Verbose, &cli.ArgDefinition{
+		Name:      "verbose",
+		UsageText: "log debug messages",
+	})
+	rootCmd.AddStringArg(&c.options.StateDir, &cli.ArgDefinition{
+		Name:      "state-dir",
+		UsageText: "directory for logs, crash reports and plugins",
+		Required:  true,
+	})
+	rootCmd.AddRunFunc(func(ctx context.Context, o []string) {
+		_, _ = fmt.Fprint(cli.Stdout(ctx), "a: ", a, ", b: ", b, "\n")
+		_, _ = fmt.Fprintln(cli.Stdout(ctx), "welcome to the thunderdome")
+	})
+
+	for _, factory := range commandFactories {
+		rootCmd.AddSubcommand(factory())
+	}
 
 	return rootCmd
 }
@@ -0,0 +1,43 @@
+package cmdbuilder
+
+import (
+	"context"
+	"fmt"
+	"github.com/sebuckler/teel/internal/middleware"
+	"github.com/sebuckler/teel/internal/services"
+	"github.com/sebuckler/teel/pkg/cli"
+)
+
+func init() {
+	registerCommand(newCreateSiteCommand)
+}
+
+func newCreateSiteCommand() cli.CommandBuilder {
+	var dir string
+	var name string
+	newCmd := cli.NewCommand("new", context.Background())
+	newCmd.AddUsageText("scaffold a new site")
+	newCmd.AddOperand(&dir, &cli.OperandDefinition{
+		Name:      "directory",
+		UsageText: "directory to create the site in",
+		Required:  true,
+	})
+	newCmd.AddOperand(&name, &cli.OperandDefinition{
+		Name:      "name",
+		UsageText: "name of the site",
+		Required:  true,
+	})
+	newCmd.AddMiddleware(middleware.SiteLock(&dir, middleware.DefaultLockTimeout))
+	newCmd.AddRunErrorFunc(func(ctx context.Context, o []string) error {
+		if scaffoldErr := services.Scaffolder(ctx).Scaffold(dir, name); scaffoldErr != nil {
+			return fmt.Errorf("failed to create site: %w", scaffoldErr)
+		}
+
+		services.Logger(ctx).Infof("created site %s in %s\n", name, dir)
+		_, _ = fmt.Fprintln(cli.Stdout(ctx), "created site "+name+" in "+dir)
+
+		return nil
+	})
+
+	return newCmd
+}
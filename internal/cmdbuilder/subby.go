@@ -0,0 +1,28 @@
+package cmdbuilder
+
+import (
+	"context"
+	"fmt"
+	"github.com/sebuckler/teel/internal/logger"
+	"github.com/sebuckler/teel/internal/services"
+	"github.com/sebuckler/teel/pkg/cli"
+)
+
+func init() {
+	registerCommand(newSubbyCommand)
+}
+
+func newSubbyCommand() cli.CommandBuilder {
+	subCmd := cli.NewCommand("subby", context.Background())
+	file := "filename"
+	subCmd.AddStringArg(&file, &cli.ArgDefinition{
+		Name:      "file",
+		ShortName: 'f',
+	})
+	subCmd.AddRunFunc(func(ctx context.Context, o []string) {
+		services.Logger(ctx).With(logger.F("file", file)).Debug("subby called")
+		_, _ = fmt.Fprintln(cli.Stdout(ctx), "and me, "+file+"!")
+	})
+
+	return subCmd
+}
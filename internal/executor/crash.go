@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sebuckler/teel/internal/fs"
+	"github.com/sebuckler/teel/pkg/cli"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+var crashReportEnv = []string{"LANG", "LC_ALL", "SHELL", "TERM"}
+
+func (e *executor) reportPanic(err error) {
+	var panicErr *cli.PanicError
+
+	if !errors.As(err, &panicErr) {
+		return
+	}
+
+	now := time.Now()
+	report := getCrashReport(panicErr, e.config.VersionInfo, e.logger.RedactText, now)
+	reportPath, reportErr := writeCrashReport(e.config.FileSystem, report, e.config.CrashDir, now)
+
+	if reportErr != nil {
+		e.logger.Infof("failed to write crash report: %v\n", reportErr)
+		_, _ = fmt.Fprintf(os.Stderr, "teel crashed and the crash report could not be saved: %v\n", reportErr)
+
+		return
+	}
+
+	e.logger.Infof("crash report written to %s\n", reportPath)
+	_, _ = fmt.Fprintf(os.Stderr, "teel crashed; a crash report was saved to %s\n", reportPath)
+}
+
+func writeCrashReport(f fs.FileSystem, r string, d string, t time.Time) (string, error) {
+	if d == "" {
+		d = os.TempDir()
+	}
+
+	if mkdirErr := f.MkdirAll(d, 0755); mkdirErr != nil {
+		return "", mkdirErr
+	}
+
+	reportPath := filepath.Join(d, "teel-crash-"+t.UTC().Format("20060102T150405Z")+".log")
+	writeErr := f.WriteFile(reportPath, []byte(r), 0600)
+
+	return reportPath, writeErr
+}
+
+func getCrashReport(p *cli.PanicError, v *cli.VersionInfo, r func(s string) string, t time.Time) string {
+	var report strings.Builder
+	workDir, _ := os.Getwd()
+
+	report.WriteString("time:         " + t.UTC().Format(time.RFC3339) + "\n")
+	report.WriteString("command line: " + r(strings.Join(os.Args, " ")) + "\n")
+
+	if v != nil {
+		report.WriteString("version:      " + v.Version + "\n")
+		report.WriteString("commit:       " + v.Commit + "\n")
+		report.WriteString("build date:   " + v.BuildDate + "\n")
+	}
+
+	report.WriteString("go:           " + runtime.Version() + "\n")
+	report.WriteString("platform:     " + runtime.GOOS + "/" + runtime.GOARCH + "\n")
+	report.WriteString("working dir:  " + workDir + "\n")
+
+	for _, key := range crashReportEnv {
+		report.WriteString(fmt.Sprintf("env %-9s %s\n", key+":", os.Getenv(key)))
+	}
+
+	report.WriteString("\n" + r(p.Error()) + "\n\n")
+	report.Write(p.Stack)
+
+	return report.String()
+}
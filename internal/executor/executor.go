@@ -1,56 +1,134 @@
 package executor
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/sebuckler/teel/internal/fs"
 	"github.com/sebuckler/teel/internal/logger"
 	"github.com/sebuckler/teel/pkg/cli"
 	"os"
 	"os/signal"
+	"syscall"
+	"time"
 )
 
 type Executor interface {
 	Execute() error
 }
 
+const (
+	DefaultGracePeriod = 10 * time.Second
+	interruptExitCode  = 130
+)
+
+type Config struct {
+	CrashDir    string
+	FileSystem  fs.FileSystem
+	GracePeriod time.Duration
+	Signals     <-chan os.Signal
+	VersionInfo *cli.VersionInfo
+}
+
 type executor struct {
+	config *Config
 	logger logger.Logger
 	runner cli.Runner
 }
 
-func New(l logger.Logger, r cli.Runner) Executor {
+func New(l logger.Logger, r cli.Runner, c *Config) Executor {
+	if c == nil {
+		c = &Config{}
+	}
+
+	if c.FileSystem == nil {
+		c.FileSystem = fs.NewOSFileSystem()
+	}
+
+	if c.GracePeriod <= 0 {
+		c.GracePeriod = DefaultGracePeriod
+	}
+
 	return &executor{
+		config: c,
 		logger: l,
 		runner: r,
 	}
 }
 
 func (e *executor) Execute() error {
-	sigChan := make(chan os.Signal, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := e.config.Signals
 	done := make(chan error, 1)
 
-	signal.Notify(sigChan, os.Interrupt, os.Kill)
+	if sigChan == nil {
+		notifyChan := make(chan os.Signal, 2)
+		sigChan = notifyChan
 
-	go func() {
-		<-sigChan
-		done <- nil
-	}()
+		signal.Notify(notifyChan, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(notifyChan)
+	}
 
 	go func() {
-		done <- e.runner.Run()
+		done <- e.runner.RunContext(ctx)
 	}()
 
-	if err := <-done; err != nil {
-		e.logger.Errorf("%v\n", err)
+	select {
+	case err := <-done:
+		return e.handleError(err)
+	case sig := <-sigChan:
+		e.logger.Warnf("received %v, stopping command\n", sig)
+		_ = e.logger.Flush()
+		cancel()
+	}
+
+	gracePeriod := time.NewTimer(e.config.GracePeriod)
+	defer gracePeriod.Stop()
+
+	select {
+	case err := <-done:
+		var exitErr *cli.ExitError
+
+		if errors.As(err, &exitErr) {
+			return e.handleError(err)
+		}
 
-		return err
+		if err == nil {
+			err = ctx.Err()
+		}
+
+		return e.handleError(&cli.ExitError{Code: interruptExitCode, Err: err})
+	case sig := <-sigChan:
+		_ = e.logger.Flush()
+
+		return e.handleError(&cli.ExitError{Code: interruptExitCode, Err: fmt.Errorf("received %v, forcing exit", sig)})
+	case <-gracePeriod.C:
+		return e.handleError(&cli.ExitError{Code: interruptExitCode, Err: fmt.Errorf("command did not stop within %s", e.config.GracePeriod)})
 	}
+}
 
-	return nil
+func (e *executor) handleError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	e.logger.WithoutConsole().Errorf("command failed: %v\n", err)
+	e.reportPanic(err)
+
+	return err
 }
 
 func Exit(err error) {
+	var exitErr *cli.ExitError
+
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.Code)
+	}
+
 	if err != nil {
-		fmt.Errorf("Error: %v\n", err)
+		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -0,0 +1,217 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"github.com/sebuckler/teel/internal/executor"
+	"github.com/sebuckler/teel/internal/fs"
+	"github.com/sebuckler/teel/internal/logger"
+	"github.com/sebuckler/teel/pkg/cli"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecutor_Execute(t *testing.T) {
+	for name, test := range getExecuteTestCases() {
+		test(t, name)
+	}
+}
+
+func getExecuteTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should return runner error":                  shouldReturnRunnerError,
+		"should cancel context on signal":             shouldCancelContextOnSignal,
+		"should keep runner exit code after signal":   shouldKeepRunnerExitCodeAfterSignal,
+		"should force exit on second signal":          shouldForceExitOnSecondSignal,
+		"should exit when grace period ends":          shouldExitWhenGracePeriodEnds,
+		"should write redacted crash report on panic": shouldWriteRedactedCrashReportOnPanic,
+		"should not write crash report without panic": shouldNotWriteCrashReportWithoutPanic,
+	}
+}
+
+type fakeRunner struct {
+	cli.Runner
+	run func(ctx context.Context) error
+}
+
+func (r *fakeRunner) RunContext(ctx context.Context) error {
+	return r.run(ctx)
+}
+
+func newBlockingRunner(started chan<- struct{}, release <-chan struct{}) *fakeRunner {
+	return &fakeRunner{run: func(ctx context.Context) error {
+		close(started)
+		<-release
+
+		return nil
+	}}
+}
+
+func execute(l logger.Logger, r cli.Runner, c *executor.Config) error {
+	return executor.New(l, r, c).Execute()
+}
+
+func getExitCode(err error) int {
+	var exitErr *cli.ExitError
+
+	if !errors.As(err, &exitErr) {
+		return -1
+	}
+
+	return exitErr.Code
+}
+
+func getCrashReports(t *testing.T, f fs.FileSystem, d string) []string {
+	var reports []string
+
+	walkErr := f.Walk(d, func(p string, i os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !i.IsDir() {
+			reports = append(reports, p)
+		}
+
+		return nil
+	})
+
+	if walkErr != nil && !os.IsNotExist(walkErr) {
+		t.Fatal(walkErr)
+	}
+
+	return reports
+}
+
+func shouldReturnRunnerError(t *testing.T, n string) {
+	expected := errors.New("build failed")
+	runner := &fakeRunner{run: func(context.Context) error { return expected }}
+
+	if err := execute(logger.New(), runner, &executor.Config{FileSystem: fs.NewMemFileSystem()}); err != expected {
+		t.Fail()
+		t.Logf("%s: got %v instead of runner error", n, err)
+	}
+}
+
+func shouldCancelContextOnSignal(t *testing.T, n string) {
+	signals := make(chan os.Signal, 2)
+	runner := &fakeRunner{run: func(ctx context.Context) error {
+		signals <- os.Interrupt
+		<-ctx.Done()
+
+		return nil
+	}}
+	err := execute(logger.New(), runner, &executor.Config{FileSystem: fs.NewMemFileSystem(), Signals: signals})
+
+	if getExitCode(err) != 130 || !errors.Is(err, context.Canceled) {
+		t.Fail()
+		t.Logf("%s: got %v instead of interrupt exit", n, err)
+	}
+}
+
+func shouldKeepRunnerExitCodeAfterSignal(t *testing.T, n string) {
+	signals := make(chan os.Signal, 2)
+	runner := &fakeRunner{run: func(ctx context.Context) error {
+		signals <- os.Interrupt
+		<-ctx.Done()
+
+		return &cli.ExitError{Code: 3, Err: errors.New("plugin stopped")}
+	}}
+	err := execute(logger.New(), runner, &executor.Config{FileSystem: fs.NewMemFileSystem(), Signals: signals})
+
+	if getExitCode(err) != 3 {
+		t.Fail()
+		t.Logf("%s: got %v instead of runner exit code", n, err)
+	}
+}
+
+func shouldForceExitOnSecondSignal(t *testing.T, n string) {
+	signals := make(chan os.Signal, 2)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	go func() {
+		<-started
+		signals <- os.Interrupt
+		signals <- os.Interrupt
+	}()
+
+	err := execute(logger.New(), newBlockingRunner(started, release), &executor.Config{
+		FileSystem:  fs.NewMemFileSystem(),
+		GracePeriod: time.Minute,
+		Signals:     signals,
+	})
+
+	if getExitCode(err) != 130 || !strings.Contains(err.Error(), "forcing exit") {
+		t.Fail()
+		t.Logf("%s: got %v instead of forced exit", n, err)
+	}
+}
+
+func shouldExitWhenGracePeriodEnds(t *testing.T, n string) {
+	signals := make(chan os.Signal, 2)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	go func() {
+		<-started
+		signals <- os.Interrupt
+	}()
+
+	start := time.Now()
+	err := execute(logger.New(), newBlockingRunner(started, release), &executor.Config{
+		FileSystem:  fs.NewMemFileSystem(),
+		GracePeriod: 50 * time.Millisecond,
+		Signals:     signals,
+	})
+
+	if getExitCode(err) != 130 || !strings.Contains(err.Error(), "did not stop within 50ms") || time.Since(start) > 5*time.Second {
+		t.Fail()
+		t.Logf("%s: got %v after %s instead of grace period exit", n, err, time.Since(start))
+	}
+}
+
+func shouldWriteRedactedCrashReportOnPanic(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	crashDir := filepath.FromSlash("/state/crash")
+	l := logger.New()
+	l.Redact("correct-horse")
+	runner := &fakeRunner{run: func(context.Context) error {
+		return &cli.PanicError{Command: "teel new", Stack: []byte("goroutine 1 [running]:\n"), Value: "bad key correct-horse"}
+	}}
+	err := execute(l, runner, &executor.Config{CrashDir: crashDir, FileSystem: memFs, VersionInfo: &cli.VersionInfo{Version: "v1.2.3"}})
+	reports := getCrashReports(t, memFs, crashDir)
+
+	if err == nil || len(reports) != 1 {
+		t.Fail()
+		t.Logf("%s: got %v and reports %v", n, err, reports)
+
+		return
+	}
+
+	content, readErr := memFs.ReadFile(reports[0])
+	report := string(content)
+
+	if readErr != nil || strings.Contains(report, "correct-horse") || !strings.Contains(report, "bad key "+logger.RedactedText) ||
+		!strings.Contains(report, "v1.2.3") || !strings.Contains(report, "goroutine 1 [running]:") {
+		t.Fail()
+		t.Log(n + ": crash report contains '" + report + "'")
+	}
+}
+
+func shouldNotWriteCrashReportWithoutPanic(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	crashDir := filepath.FromSlash("/state/crash")
+	runner := &fakeRunner{run: func(context.Context) error { return errors.New("build failed") }}
+	_ = execute(logger.New(), runner, &executor.Config{CrashDir: crashDir, FileSystem: memFs})
+
+	if reports := getCrashReports(t, memFs, crashDir); len(reports) != 0 {
+		t.Fail()
+		t.Logf("%s: wrote crash reports %v", n, reports)
+	}
+}
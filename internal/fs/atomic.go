@@ -0,0 +1,171 @@
+package fs
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type AtomicFileWriter interface {
+	Abort() error
+	Close() error
+	io.Writer
+}
+
+type atomicFileWriter struct {
+	closed bool
+	err    error
+	file   *os.File
+	mode   os.FileMode
+	mutex  sync.Mutex
+	path   string
+}
+
+func OpenAtomicFileWriter(p string, m os.FileMode) (*atomicFileWriter, error) {
+	dir, base := filepath.Split(p)
+
+	if dir == "" {
+		dir = "."
+	}
+
+	if info, statErr := os.Stat(p); statErr == nil {
+		if info.IsDir() {
+			return nil, &os.PathError{Op: "open", Path: p, Err: errors.New("is a directory")}
+		}
+
+		m = info.Mode().Perm()
+	} else if !os.IsNotExist(statErr) {
+		return nil, statErr
+	}
+
+	file, fileErr := ioutil.TempFile(dir, "."+base+".tmp-")
+
+	if fileErr != nil {
+		return nil, fileErr
+	}
+
+	return &atomicFileWriter{
+		file: file,
+		mode: m,
+		path: p,
+	}, nil
+}
+
+func WriteFileAtomic(p string, d []byte, m os.FileMode) error {
+	writer, writerErr := OpenAtomicFileWriter(p, m)
+
+	if writerErr != nil {
+		return writerErr
+	}
+
+	if _, writeErr := writer.Write(d); writeErr != nil {
+		_ = writer.Abort()
+
+		return writeErr
+	}
+
+	return writer.Close()
+}
+
+func (a *atomicFileWriter) Abort() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return nil
+	}
+
+	return a.discard()
+}
+
+func (a *atomicFileWriter) Close() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return a.err
+	}
+
+	if a.err != nil {
+		writeErr := a.err
+		_ = a.discard()
+		a.err = writeErr
+
+		return writeErr
+	}
+
+	if syncErr := a.file.Sync(); syncErr != nil {
+		return a.fail(syncErr)
+	}
+
+	if chmodErr := a.file.Chmod(a.mode); chmodErr != nil {
+		return a.fail(chmodErr)
+	}
+
+	if closeErr := a.file.Close(); closeErr != nil {
+		return a.fail(closeErr)
+	}
+
+	if renameErr := os.Rename(a.file.Name(), a.path); renameErr != nil {
+		_ = os.Remove(a.file.Name())
+		a.closed = true
+		a.err = renameErr
+
+		return renameErr
+	}
+
+	a.closed = true
+	syncDir(filepath.Dir(a.path))
+
+	return nil
+}
+
+func (a *atomicFileWriter) Write(p []byte) (int, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return 0, os.ErrClosed
+	}
+
+	if a.err != nil {
+		return 0, a.err
+	}
+
+	n, writeErr := a.file.Write(p)
+
+	if writeErr != nil {
+		a.err = writeErr
+	}
+
+	return n, writeErr
+}
+
+func (a *atomicFileWriter) discard() error {
+	a.closed = true
+	a.err = os.ErrClosed
+	_ = a.file.Close()
+
+	return os.Remove(a.file.Name())
+}
+
+func (a *atomicFileWriter) fail(err error) error {
+	_ = a.discard()
+	a.err = err
+
+	return err
+}
+
+func syncDir(d string) {
+	dir, openErr := os.Open(d)
+
+	if openErr != nil {
+		return
+	}
+
+	_ = dir.Sync()
+	_ = dir.Close()
+}
@@ -0,0 +1,177 @@
+package fs_test
+
+import (
+	"github.com/sebuckler/teel/internal/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAtomicFileWriter(t *testing.T) {
+	for name, test := range getAtomicFileWriterTestCases() {
+		test(t, name)
+	}
+}
+
+func getAtomicFileWriterTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should replace file on close":                  shouldReplaceFileOnClose,
+		"should keep original and remove temp on abort": shouldKeepOriginalAndRemoveTempOnAbort,
+		"should remove temp when rename fails":          shouldRemoveTempWhenRenameFails,
+		"should preserve existing file mode":            shouldPreserveExistingFileMode,
+		"should error writing after close":              shouldErrorWritingAfterClose,
+		"should error opening directory":                shouldErrorOpeningDirectory,
+	}
+}
+
+func getTempFiles(t *testing.T, d string) []string {
+	entries, readErr := ioutil.ReadDir(d)
+
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+
+	var temps []string
+
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			temps = append(temps, entry.Name())
+		}
+	}
+
+	return temps
+}
+
+func shouldReplaceFileOnClose(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "teel.json")
+
+	if writeErr := ioutil.WriteFile(p, []byte("old"), 0644); writeErr != nil {
+		t.Fatal(writeErr)
+	}
+
+	if writeErr := fs.WriteFileAtomic(p, []byte("new"), 0644); writeErr != nil {
+		t.Fatal(writeErr)
+	}
+
+	if content, _ := ioutil.ReadFile(p); string(content) != "new" || len(getTempFiles(t, dir)) != 0 {
+		t.Fail()
+		t.Log(n + ": file contains '" + string(content) + "' or temp file left behind")
+	}
+}
+
+func shouldKeepOriginalAndRemoveTempOnAbort(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "teel.json")
+
+	if writeErr := ioutil.WriteFile(p, []byte("old"), 0644); writeErr != nil {
+		t.Fatal(writeErr)
+	}
+
+	writer, openErr := fs.OpenAtomicFileWriter(p, 0644)
+
+	if openErr != nil {
+		t.Fatal(openErr)
+	}
+
+	writeEntries(t, writer, "partial")
+
+	if abortErr := writer.Abort(); abortErr != nil {
+		t.Fatal(abortErr)
+	}
+
+	if content, _ := ioutil.ReadFile(p); string(content) != "old" || len(getTempFiles(t, dir)) != 0 {
+		t.Fail()
+		t.Log(n + ": file contains '" + string(content) + "' or temp file left behind")
+	}
+}
+
+func shouldRemoveTempWhenRenameFails(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "teel.json")
+	writer, openErr := fs.OpenAtomicFileWriter(p, 0644)
+
+	if openErr != nil {
+		t.Fatal(openErr)
+	}
+
+	writeEntries(t, writer, "content")
+
+	if mkdirErr := os.MkdirAll(filepath.Join(p, "child"), 0755); mkdirErr != nil {
+		t.Fatal(mkdirErr)
+	}
+
+	closeErr := writer.Close()
+
+	if closeErr == nil || len(getTempFiles(t, dir)) != 0 {
+		t.Fail()
+		t.Log(n + ": rename error not returned or temp file left behind")
+	}
+
+	if writer.Close() != closeErr {
+		t.Fail()
+		t.Log(n + ": second close did not return rename error")
+	}
+}
+
+func shouldPreserveExistingFileMode(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "deploy.sh")
+
+	if writeErr := ioutil.WriteFile(p, []byte("old"), 0755); writeErr != nil {
+		t.Fatal(writeErr)
+	}
+
+	if chmodErr := os.Chmod(p, 0750); chmodErr != nil {
+		t.Fatal(chmodErr)
+	}
+
+	if writeErr := fs.WriteFileAtomic(p, []byte("new"), 0644); writeErr != nil {
+		t.Fatal(writeErr)
+	}
+
+	if info, statErr := os.Stat(p); statErr != nil || info.Mode().Perm() != 0750 {
+		t.Fail()
+		t.Log(n + ": existing file mode not preserved")
+	}
+}
+
+func shouldErrorWritingAfterClose(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	writer, openErr := fs.OpenAtomicFileWriter(filepath.Join(dir, "teel.json"), 0644)
+
+	if openErr != nil {
+		t.Fatal(openErr)
+	}
+
+	if closeErr := writer.Close(); closeErr != nil {
+		t.Fatal(closeErr)
+	}
+
+	if _, writeErr := writer.Write([]byte("late")); writeErr == nil {
+		t.Fail()
+		t.Log(n + ": wrote after close")
+	}
+}
+
+func shouldErrorOpeningDirectory(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	if _, openErr := fs.OpenAtomicFileWriter(dir, 0644); openErr == nil {
+		t.Fail()
+		t.Log(n + ": opened directory for atomic write")
+	}
+}
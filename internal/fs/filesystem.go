@@ -0,0 +1,56 @@
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type FileSystem interface {
+	MkdirAll(p string, m os.FileMode) error
+	ReadFile(p string) ([]byte, error)
+	Remove(p string) error
+	RemoveAll(p string) error
+	Rename(o string, n string) error
+	Stat(p string) (os.FileInfo, error)
+	Walk(r string, f filepath.WalkFunc) error
+	WriteFile(p string, d []byte, m os.FileMode) error
+}
+
+type osFileSystem struct{}
+
+func NewOSFileSystem() FileSystem {
+	return &osFileSystem{}
+}
+
+func (o *osFileSystem) MkdirAll(p string, m os.FileMode) error {
+	return os.MkdirAll(p, m)
+}
+
+func (o *osFileSystem) ReadFile(p string) ([]byte, error) {
+	return ioutil.ReadFile(p)
+}
+
+func (o *osFileSystem) Remove(p string) error {
+	return os.Remove(p)
+}
+
+func (o *osFileSystem) RemoveAll(p string) error {
+	return os.RemoveAll(p)
+}
+
+func (o *osFileSystem) Rename(old string, n string) error {
+	return os.Rename(old, n)
+}
+
+func (o *osFileSystem) Stat(p string) (os.FileInfo, error) {
+	return os.Stat(p)
+}
+
+func (o *osFileSystem) Walk(r string, f filepath.WalkFunc) error {
+	return filepath.Walk(r, f)
+}
+
+func (o *osFileSystem) WriteFile(p string, d []byte, m os.FileMode) error {
+	return WriteFileAtomic(p, d, m)
+}
@@ -1,42 +1,142 @@
 package fs
 
 import (
-	"bufio"
+	"bytes"
 	"io"
 	"os"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultFlushInterval = time.Second
+	DefaultFlushSize     = 32 * 1024
 )
 
 type BufferedFileWriter interface {
 	Close() error
+	Flush() error
 	io.Writer
 }
 
+type BufferConfig struct {
+	FlushInterval time.Duration
+	FlushSize     int
+}
+
 type bufferedFileWriter struct {
-	file *os.File
-	writer *bufio.Writer
+	buffer bytes.Buffer
+	closed bool
+	config *BufferConfig
+	done   chan struct{}
+	mutex  sync.Mutex
+	stop   sync.WaitGroup
+	writer io.WriteCloser
+}
+
+func DefaultBufferConfig() *BufferConfig {
+	return &BufferConfig{
+		FlushInterval: DefaultFlushInterval,
+		FlushSize:     DefaultFlushSize,
+	}
 }
 
-func OpenBufferedFileWriter(p string) (*bufferedFileWriter, error) {
-	file, fileErr := os.OpenFile(p, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0755)
+func NewBufferedWriter(w io.WriteCloser, c *BufferConfig) *bufferedFileWriter {
+	if c == nil {
+		c = DefaultBufferConfig()
+	}
 
-	if fileErr != nil {
-		return nil, fileErr
+	writer := &bufferedFileWriter{
+		config: c,
+		done:   make(chan struct{}),
+		writer: w,
 	}
 
-	return &bufferedFileWriter{
-		file:   file,
-		writer: bufio.NewWriter(file),
-	}, nil
+	if c.FlushInterval > 0 {
+		writer.stop.Add(1)
+
+		go writer.flushPeriodically()
+	}
+
+	return writer
 }
 
 func (b *bufferedFileWriter) Close() error {
-	if flushErr := b.writer.Flush(); flushErr != nil {
+	b.mutex.Lock()
+
+	if b.closed {
+		b.mutex.Unlock()
+
+		return nil
+	}
+
+	b.closed = true
+	close(b.done)
+	b.mutex.Unlock()
+	b.stop.Wait()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	flushErr := b.flush()
+	closeErr := b.writer.Close()
+
+	if flushErr != nil {
 		return flushErr
 	}
 
-	return b.file.Close()
+	return closeErr
+}
+
+func (b *bufferedFileWriter) Flush() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.flush()
 }
 
 func (b *bufferedFileWriter) Write(p []byte) (int, error) {
-	return b.writer.Write(p)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return 0, os.ErrClosed
+	}
+
+	n, _ := b.buffer.Write(p)
+
+	if b.config.FlushSize <= 0 || b.buffer.Len() >= b.config.FlushSize {
+		if flushErr := b.flush(); flushErr != nil {
+			return n, flushErr
+		}
+	}
+
+	return n, nil
+}
+
+func (b *bufferedFileWriter) flush() error {
+	if b.buffer.Len() == 0 {
+		return nil
+	}
+
+	_, writeErr := b.writer.Write(b.buffer.Bytes())
+	b.buffer.Reset()
+
+	return writeErr
+}
+
+func (b *bufferedFileWriter) flushPeriodically() {
+	defer b.stop.Done()
+
+	ticker := time.NewTicker(b.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = b.Flush()
+		case <-b.done:
+			return
+		}
+	}
 }
@@ -0,0 +1,142 @@
+package fs_test
+
+import (
+	"bytes"
+	"github.com/sebuckler/teel/internal/fs"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBufferedWriter(t *testing.T) {
+	for name, test := range getBufferedWriterTestCases() {
+		test(t, name)
+	}
+}
+
+func getBufferedWriterTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should flush when size reached":        shouldFlushWhenSizeReached,
+		"should flush on interval":              shouldFlushOnInterval,
+		"should flush every write without size": shouldFlushEveryWriteWithoutSize,
+		"should flush and close on close":       shouldFlushAndCloseOnClose,
+		"should error writing after close":      shouldErrorBufferedWriteAfterClose,
+	}
+}
+
+type recordingWriter struct {
+	buffer bytes.Buffer
+	closed bool
+	mutex  sync.Mutex
+	writes int
+}
+
+func (r *recordingWriter) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.closed = true
+
+	return nil
+}
+
+func (r *recordingWriter) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.writes++
+
+	return r.buffer.Write(p)
+}
+
+func (r *recordingWriter) String() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.buffer.String()
+}
+
+func shouldFlushWhenSizeReached(t *testing.T, n string) {
+	target := &recordingWriter{}
+	writer := fs.NewBufferedWriter(target, &fs.BufferConfig{FlushSize: 8})
+	defer writer.Close()
+
+	writeEntries(t, writer, "abc\n")
+
+	if target.String() != "" {
+		t.Fail()
+		t.Log(n + ": flushed '" + target.String() + "' before size reached")
+	}
+
+	writeEntries(t, writer, "defg\n")
+
+	if target.String() != "abc\ndefg\n" || target.writes != 1 {
+		t.Fail()
+		t.Log(n + ": flushed '" + target.String() + "' after size reached")
+	}
+}
+
+func shouldFlushOnInterval(t *testing.T, n string) {
+	target := &recordingWriter{}
+	writer := fs.NewBufferedWriter(target, &fs.BufferConfig{FlushInterval: 10 * time.Millisecond, FlushSize: 4096})
+	defer writer.Close()
+
+	writeEntries(t, writer, "entry\n")
+	deadline := time.Now().Add(2 * time.Second)
+
+	for target.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if target.String() != "entry\n" {
+		t.Fail()
+		t.Log(n + ": buffer not flushed on interval")
+	}
+}
+
+func shouldFlushEveryWriteWithoutSize(t *testing.T, n string) {
+	target := &recordingWriter{}
+	writer := fs.NewBufferedWriter(target, &fs.BufferConfig{})
+	defer writer.Close()
+
+	writeEntries(t, writer, "one\n", "two\n")
+
+	if target.String() != "one\ntwo\n" || target.writes != 2 {
+		t.Fail()
+		t.Log(n + ": flushed '" + target.String() + "' without flush size")
+	}
+}
+
+func shouldFlushAndCloseOnClose(t *testing.T, n string) {
+	target := &recordingWriter{}
+	writer := fs.NewBufferedWriter(target, &fs.BufferConfig{FlushInterval: time.Hour, FlushSize: 4096})
+
+	writeEntries(t, writer, "entry\n")
+
+	if closeErr := writer.Close(); closeErr != nil {
+		t.Fatal(closeErr)
+	}
+
+	if target.String() != "entry\n" || !target.closed {
+		t.Fail()
+		t.Log(n + ": buffer not flushed or writer not closed")
+	}
+
+	if closeErr := writer.Close(); closeErr != nil {
+		t.Fail()
+		t.Log(n + ": second close returned " + closeErr.Error())
+	}
+}
+
+func shouldErrorBufferedWriteAfterClose(t *testing.T, n string) {
+	writer := fs.NewBufferedWriter(&recordingWriter{}, nil)
+
+	if closeErr := writer.Close(); closeErr != nil {
+		t.Fatal(closeErr)
+	}
+
+	if _, writeErr := writer.Write([]byte("late")); writeErr == nil {
+		t.Fail()
+		t.Log(n + ": wrote after close")
+	}
+}
@@ -0,0 +1,62 @@
+//go:build !windows
+// +build !windows
+
+package fs
+
+import (
+	"os"
+	"syscall"
+)
+
+func lockFile(f *os.File) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+func tryLockFile(f *os.File) (bool, error) {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+
+		switch err {
+		case nil:
+			return true, nil
+		case syscall.EWOULDBLOCK:
+			return false, nil
+		case syscall.EINTR:
+			continue
+		default:
+			return false, err
+		}
+	}
+}
+
+func releaseLockFile(f *os.File, p string) error {
+	removeErr := os.Remove(p)
+	unlockErr := unlockFile(f)
+	closeErr := f.Close()
+
+	if removeErr != nil && !os.IsNotExist(removeErr) {
+		return removeErr
+	}
+
+	if unlockErr != nil {
+		return unlockErr
+	}
+
+	return closeErr
+}
+
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
+
+func processExists(p int) bool {
+	err := syscall.Kill(p, 0)
+
+	return err == nil || err == syscall.EPERM
+}
@@ -0,0 +1,104 @@
+//go:build windows
+// +build windows
+
+package fs
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	errorLockViolation             = syscall.Errno(33)
+	lockfileExclusiveLock          = 0x00000002
+	lockfileFailImmediately        = 0x00000001
+	processQueryLimitedInformation = 0x00001000
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+func lockFile(f *os.File) error {
+	var overlapped syscall.Overlapped
+	result, _, err := procLockFileEx.Call(
+		f.Fd(),
+		uintptr(lockfileExclusiveLock),
+		0,
+		1,
+		0,
+		uintptr(unsafe.Pointer(&overlapped)),
+	)
+
+	if result == 0 {
+		return err
+	}
+
+	return nil
+}
+
+func tryLockFile(f *os.File) (bool, error) {
+	var overlapped syscall.Overlapped
+	result, _, err := procLockFileEx.Call(
+		f.Fd(),
+		uintptr(lockfileExclusiveLock|lockfileFailImmediately),
+		0,
+		1,
+		0,
+		uintptr(unsafe.Pointer(&overlapped)),
+	)
+
+	if result != 0 {
+		return true, nil
+	}
+
+	if err == errorLockViolation {
+		return false, nil
+	}
+
+	return false, err
+}
+
+func releaseLockFile(f *os.File, p string) error {
+	unlockErr := unlockFile(f)
+	closeErr := f.Close()
+	_ = os.Remove(p)
+
+	if unlockErr != nil {
+		return unlockErr
+	}
+
+	return closeErr
+}
+
+func unlockFile(f *os.File) error {
+	var overlapped syscall.Overlapped
+	result, _, err := procUnlockFileEx.Call(
+		f.Fd(),
+		0,
+		1,
+		0,
+		uintptr(unsafe.Pointer(&overlapped)),
+	)
+
+	if result == 0 {
+		return err
+	}
+
+	return nil
+}
+
+func processExists(p int) bool {
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(p))
+
+	if err != nil {
+		return false
+	}
+
+	_ = syscall.CloseHandle(handle)
+
+	return true
+}
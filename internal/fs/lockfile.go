@@ -0,0 +1,250 @@
+package fs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const lockRetryInterval = 100 * time.Millisecond
+
+type LockInfo struct {
+	Command string    `json:"command"`
+	Host    string    `json:"host"`
+	PID     int       `json:"pid"`
+	Started time.Time `json:"started"`
+}
+
+type LockHeldError struct {
+	Holder *LockInfo
+	Path   string
+	Stale  bool
+}
+
+type FileLock struct {
+	createdDir string
+	file       *os.File
+	Path       string
+	Stale      *LockInfo
+}
+
+func AcquireLock(ctx context.Context, p string, c string, t time.Duration) (*FileLock, error) {
+	createdDir := getMissingDir(filepath.Dir(p))
+
+	if mkdirErr := os.MkdirAll(filepath.Dir(p), 0755); mkdirErr != nil {
+		return nil, mkdirErr
+	}
+
+	deadline := time.Now().Add(t)
+
+	for {
+		file, holder, lockErr := tryLockPath(p)
+
+		if lockErr != nil {
+			return nil, lockErr
+		}
+
+		if file != nil {
+			lock := &FileLock{
+				createdDir: createdDir,
+				file:       file,
+				Path:       p,
+				Stale:      holder,
+			}
+
+			if writeErr := lock.writeInfo(c); writeErr != nil {
+				_ = lock.Unlock()
+
+				return nil, writeErr
+			}
+
+			return lock, nil
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, &LockHeldError{
+				Holder: holder,
+				Path:   p,
+				Stale:  isStaleHolder(holder),
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+func (l *FileLock) Unlock() error {
+	truncateErr := l.file.Truncate(0)
+	releaseErr := releaseLockFile(l.file, l.Path)
+
+	if l.createdDir != "" {
+		removeEmptyDirs(filepath.Dir(l.Path), l.createdDir)
+	}
+
+	if truncateErr != nil {
+		return truncateErr
+	}
+
+	return releaseErr
+}
+
+func (l *FileLock) writeInfo(c string) error {
+	host, _ := os.Hostname()
+	data, jsonErr := json.Marshal(&LockInfo{
+		Command: c,
+		Host:    host,
+		PID:     os.Getpid(),
+		Started: time.Now().UTC(),
+	})
+
+	if jsonErr != nil {
+		return jsonErr
+	}
+
+	if truncateErr := l.file.Truncate(0); truncateErr != nil {
+		return truncateErr
+	}
+
+	if _, writeErr := l.file.WriteAt(data, 0); writeErr != nil {
+		return writeErr
+	}
+
+	return l.file.Sync()
+}
+
+func (e *LockHeldError) Error() string {
+	if e.Holder == nil {
+		return fmt.Sprintf("%s is locked by another process", e.Path)
+	}
+
+	message := fmt.Sprintf("%s is locked by pid %d on %s since %s (%s)", e.Path, e.Holder.PID, e.Holder.Host,
+		e.Holder.Started.Local().Format(time.RFC3339), e.Holder.Command)
+
+	if e.Stale {
+		message += "; the holder is no longer running but a child process still holds the lock"
+	}
+
+	return message
+}
+
+func isStaleHolder(i *LockInfo) bool {
+	if i == nil {
+		return false
+	}
+
+	host, hostErr := os.Hostname()
+
+	return hostErr == nil && i.Host == host && !processExists(i.PID)
+}
+
+func getMissingDir(d string) string {
+	missing := ""
+
+	for {
+		if _, statErr := os.Stat(d); !os.IsNotExist(statErr) {
+			return missing
+		}
+
+		missing = d
+		parent := filepath.Dir(d)
+
+		if parent == d {
+			return missing
+		}
+
+		d = parent
+	}
+}
+
+func isLockedPath(f *os.File, p string) bool {
+	fileInfo, fileErr := f.Stat()
+	pathInfo, pathErr := os.Stat(p)
+
+	return fileErr == nil && pathErr == nil && os.SameFile(fileInfo, pathInfo)
+}
+
+func removeEmptyDirs(d string, r string) {
+	for {
+		if removeErr := os.Remove(d); removeErr != nil || d == r {
+			return
+		}
+
+		parent := filepath.Dir(d)
+
+		if parent == d {
+			return
+		}
+
+		d = parent
+	}
+}
+
+func tryLockPath(p string) (*os.File, *LockInfo, error) {
+	for {
+		file, fileErr := os.OpenFile(p, os.O_RDWR|os.O_CREATE, 0600)
+
+		if os.IsNotExist(fileErr) {
+			if mkdirErr := os.MkdirAll(filepath.Dir(p), 0755); mkdirErr != nil {
+				return nil, nil, mkdirErr
+			}
+
+			continue
+		}
+
+		if fileErr != nil {
+			return nil, nil, fileErr
+		}
+
+		locked, lockErr := tryLockFile(file)
+
+		if lockErr != nil {
+			_ = file.Close()
+
+			return nil, nil, lockErr
+		}
+
+		holder := readLockInfo(file)
+
+		if !locked {
+			_ = file.Close()
+
+			return nil, holder, nil
+		}
+
+		if isLockedPath(file, p) {
+			return file, holder, nil
+		}
+
+		_ = unlockFile(file)
+		_ = file.Close()
+	}
+}
+
+func readLockInfo(f *os.File) *LockInfo {
+	if _, seekErr := f.Seek(0, 0); seekErr != nil {
+		return nil
+	}
+
+	data, readErr := ioutil.ReadAll(f)
+
+	if readErr != nil || len(strings.TrimSpace(string(data))) == 0 {
+		return nil
+	}
+
+	info := &LockInfo{}
+
+	if jsonErr := json.Unmarshal(data, info); jsonErr != nil {
+		return nil
+	}
+
+	return info
+}
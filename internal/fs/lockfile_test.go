@@ -0,0 +1,254 @@
+package fs_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sebuckler/teel/internal/fs"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquireLock(t *testing.T) {
+	for name, test := range getAcquireLockTestCases() {
+		test(t, name)
+	}
+}
+
+func getAcquireLockTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should report holder when lock is held":       shouldReportHolderWhenLockIsHeld,
+		"should acquire lock after holder unlocks":     shouldAcquireLockAfterHolderUnlocks,
+		"should detect stale holder on same host":      shouldDetectStaleHolderOnSameHost,
+		"should not detect stale holder on other host": shouldNotDetectStaleHolderOnOtherHost,
+		"should report info left by crashed holder":    shouldReportInfoLeftByCrashedHolder,
+		"should remove created lock dirs after unlock": shouldRemoveCreatedLockDirsAfterUnlock,
+		"should keep existing lock dir after unlock":   shouldKeepExistingLockDirAfterUnlock,
+		"should stop waiting when context is done":     shouldStopWaitingWhenContextIsDone,
+	}
+}
+
+func getExitedPID(t *testing.T) int {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+
+	if runErr := cmd.Run(); runErr != nil {
+		t.Fatal(runErr)
+	}
+
+	return cmd.Process.Pid
+}
+
+func writeLockInfo(t *testing.T, p string, i *fs.LockInfo) {
+	data, jsonErr := json.Marshal(i)
+
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+
+	if writeErr := ioutil.WriteFile(p, data, 0600); writeErr != nil {
+		t.Fatal(writeErr)
+	}
+}
+
+func shouldReportHolderWhenLockIsHeld(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "locks", "site.lock")
+	lock, lockErr := fs.AcquireLock(context.Background(), p, "teel new", 0)
+
+	if lockErr != nil {
+		t.Fatal(lockErr)
+	}
+
+	defer lock.Unlock()
+
+	_, heldErr := fs.AcquireLock(context.Background(), p, "teel build", 0)
+	held, ok := heldErr.(*fs.LockHeldError)
+
+	if !ok || held.Stale || held.Holder == nil || held.Holder.PID != os.Getpid() || held.Holder.Command != "teel new" {
+		t.Fail()
+		t.Logf("%s: got %v instead of lock held error", n, heldErr)
+	}
+}
+
+func shouldAcquireLockAfterHolderUnlocks(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "site.lock")
+	lock, lockErr := fs.AcquireLock(context.Background(), p, "teel new", 0)
+
+	if lockErr != nil {
+		t.Fatal(lockErr)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = lock.Unlock()
+	}()
+
+	next, nextErr := fs.AcquireLock(context.Background(), p, "teel build", 2*time.Second)
+
+	if nextErr != nil {
+		t.Fail()
+		t.Log(n + ": " + nextErr.Error())
+
+		return
+	}
+
+	defer next.Unlock()
+
+	if next.Stale != nil {
+		t.Fail()
+		t.Log(n + ": cleanly released lock reported as stale")
+	}
+}
+
+func shouldDetectStaleHolderOnSameHost(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "site.lock")
+	lock, lockErr := fs.AcquireLock(context.Background(), p, "teel new", 0)
+
+	if lockErr != nil {
+		t.Fatal(lockErr)
+	}
+
+	defer lock.Unlock()
+
+	host, _ := os.Hostname()
+	writeLockInfo(t, p, &fs.LockInfo{Command: "teel serve", Host: host, PID: getExitedPID(t)})
+
+	_, heldErr := fs.AcquireLock(context.Background(), p, "teel build", 0)
+
+	if held, ok := heldErr.(*fs.LockHeldError); !ok || !held.Stale {
+		t.Fail()
+		t.Logf("%s: got %v instead of stale lock held error", n, heldErr)
+	}
+}
+
+func shouldNotDetectStaleHolderOnOtherHost(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "site.lock")
+	lock, lockErr := fs.AcquireLock(context.Background(), p, "teel new", 0)
+
+	if lockErr != nil {
+		t.Fatal(lockErr)
+	}
+
+	defer lock.Unlock()
+
+	writeLockInfo(t, p, &fs.LockInfo{Command: "teel serve", Host: "build-agent-7", PID: getExitedPID(t)})
+
+	_, heldErr := fs.AcquireLock(context.Background(), p, "teel build", 0)
+
+	if held, ok := heldErr.(*fs.LockHeldError); !ok || held.Stale {
+		t.Fail()
+		t.Logf("%s: got %v instead of live lock held error", n, heldErr)
+	}
+}
+
+func shouldReportInfoLeftByCrashedHolder(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "site.lock")
+	writeLockInfo(t, p, &fs.LockInfo{Command: "teel serve", Host: "laptop", PID: 42})
+
+	lock, lockErr := fs.AcquireLock(context.Background(), p, "teel build", 0)
+
+	if lockErr != nil {
+		t.Fatal(lockErr)
+	}
+
+	if lock.Stale == nil || lock.Stale.Command != "teel serve" || lock.Stale.PID != 42 {
+		t.Fail()
+		t.Log(n + ": crashed holder info not reported")
+	}
+
+	if unlockErr := lock.Unlock(); unlockErr != nil {
+		t.Fatal(unlockErr)
+	}
+
+	if _, statErr := os.Stat(p); !os.IsNotExist(statErr) {
+		t.Fail()
+		t.Log(n + ": lock file not removed after unlock")
+	}
+}
+
+func shouldRemoveCreatedLockDirsAfterUnlock(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	site := filepath.Join(dir, "blog")
+	lock, lockErr := fs.AcquireLock(context.Background(), filepath.Join(site, ".teel", "site.lock"), "teel new", 0)
+
+	if lockErr != nil {
+		t.Fatal(lockErr)
+	}
+
+	if unlockErr := lock.Unlock(); unlockErr != nil {
+		t.Fatal(unlockErr)
+	}
+
+	if _, statErr := os.Stat(site); !os.IsNotExist(statErr) {
+		t.Fail()
+		t.Log(n + ": directories created for the lock not removed")
+	}
+}
+
+func shouldKeepExistingLockDirAfterUnlock(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	lockDir := filepath.Join(dir, ".teel")
+
+	if mkdirErr := os.MkdirAll(filepath.Join(lockDir, "logs"), 0755); mkdirErr != nil {
+		t.Fatal(mkdirErr)
+	}
+	lock, lockErr := fs.AcquireLock(context.Background(), filepath.Join(lockDir, "site.lock"), "teel new", 0)
+
+	if lockErr != nil {
+		t.Fatal(lockErr)
+	}
+
+	if unlockErr := lock.Unlock(); unlockErr != nil {
+		t.Fatal(unlockErr)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(lockDir, "logs")); statErr != nil {
+		t.Fail()
+		t.Log(n + ": existing lock dir removed after unlock")
+	}
+}
+
+func shouldStopWaitingWhenContextIsDone(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "site.lock")
+	lock, lockErr := fs.AcquireLock(context.Background(), p, "teel new", 0)
+
+	if lockErr != nil {
+		t.Fatal(lockErr)
+	}
+
+	defer lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, waitErr := fs.AcquireLock(ctx, p, "teel build", time.Minute)
+
+	if waitErr != context.DeadlineExceeded || time.Since(start) > 5*time.Second {
+		t.Fail()
+		t.Logf("%s: got %v after %s instead of context error", n, waitErr, time.Since(start))
+	}
+}
@@ -0,0 +1,330 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type memFileSystem struct {
+	files map[string]*memFile
+	mutex sync.RWMutex
+}
+
+type memFile struct {
+	data    []byte
+	mode    os.FileMode
+	modTime time.Time
+}
+
+type memFileInfo struct {
+	file *memFile
+	name string
+}
+
+func NewMemFileSystem() FileSystem {
+	return &memFileSystem{
+		files: map[string]*memFile{},
+	}
+}
+
+func (m *memFileSystem) MkdirAll(p string, mode os.FileMode) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	p = filepath.Clean(p)
+	var missing []string
+
+	for dir := p; !isMemRoot(dir); dir = filepath.Dir(dir) {
+		file, ok := m.files[dir]
+
+		if ok && !file.mode.IsDir() {
+			return &os.PathError{Op: "mkdir", Path: dir, Err: syscall.ENOTDIR}
+		}
+
+		if ok {
+			break
+		}
+
+		missing = append(missing, dir)
+	}
+
+	for _, dir := range missing {
+		m.files[dir] = &memFile{mode: os.ModeDir | mode.Perm(), modTime: time.Now()}
+	}
+
+	return nil
+}
+
+func (m *memFileSystem) ReadFile(p string) ([]byte, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	file, ok := m.files[filepath.Clean(p)]
+
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: p, Err: os.ErrNotExist}
+	}
+
+	if file.mode.IsDir() {
+		return nil, &os.PathError{Op: "read", Path: p, Err: syscall.EISDIR}
+	}
+
+	data := make([]byte, len(file.data))
+	copy(data, file.data)
+
+	return data, nil
+}
+
+func (m *memFileSystem) Remove(p string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	p = filepath.Clean(p)
+	file, ok := m.files[p]
+
+	if !ok {
+		return &os.PathError{Op: "remove", Path: p, Err: os.ErrNotExist}
+	}
+
+	if file.mode.IsDir() && len(m.getChildren(p)) > 0 {
+		return &os.PathError{Op: "remove", Path: p, Err: syscall.ENOTEMPTY}
+	}
+
+	delete(m.files, p)
+
+	return nil
+}
+
+func (m *memFileSystem) RemoveAll(p string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	p = filepath.Clean(p)
+
+	for name := range m.files {
+		if name == p || isMemDescendant(name, p) {
+			delete(m.files, name)
+		}
+	}
+
+	return nil
+}
+
+func (m *memFileSystem) Rename(o string, n string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	o = filepath.Clean(o)
+	n = filepath.Clean(n)
+	file, ok := m.files[o]
+
+	if !ok {
+		return &os.LinkError{Op: "rename", Old: o, New: n, Err: os.ErrNotExist}
+	}
+
+	if parentErr := m.checkParent(n); parentErr != nil {
+		return &os.LinkError{Op: "rename", Old: o, New: n, Err: parentErr}
+	}
+
+	if target, exists := m.files[n]; exists && target.mode.IsDir() != file.mode.IsDir() {
+		return &os.LinkError{Op: "rename", Old: o, New: n, Err: syscall.EEXIST}
+	} else if exists && target.mode.IsDir() && len(m.getChildren(n)) > 0 {
+		return &os.LinkError{Op: "rename", Old: o, New: n, Err: syscall.ENOTEMPTY}
+	}
+
+	if file.mode.IsDir() && isMemDescendant(n, o) {
+		return &os.LinkError{Op: "rename", Old: o, New: n, Err: syscall.EINVAL}
+	}
+
+	moved := map[string]*memFile{}
+
+	for name, child := range m.files {
+		if isMemDescendant(name, o) {
+			moved[n+strings.TrimPrefix(name, o)] = child
+			delete(m.files, name)
+		}
+	}
+
+	for name, child := range moved {
+		m.files[name] = child
+	}
+
+	delete(m.files, o)
+	m.files[n] = file
+
+	return nil
+}
+
+func (m *memFileSystem) Stat(p string) (os.FileInfo, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	p = filepath.Clean(p)
+
+	if isMemRoot(p) {
+		return &memFileInfo{file: &memFile{mode: os.ModeDir | 0755}, name: p}, nil
+	}
+
+	file, ok := m.files[p]
+
+	if !ok {
+		return nil, &os.PathError{Op: "stat", Path: p, Err: os.ErrNotExist}
+	}
+
+	return &memFileInfo{file: file, name: filepath.Base(p)}, nil
+}
+
+func (m *memFileSystem) Walk(r string, f filepath.WalkFunc) error {
+	r = filepath.Clean(r)
+	info, statErr := m.Stat(r)
+
+	if statErr != nil {
+		return f(r, nil, statErr)
+	}
+
+	walkErr := m.walk(r, info, f)
+
+	if walkErr == filepath.SkipDir {
+		return nil
+	}
+
+	return walkErr
+}
+
+func (m *memFileSystem) WriteFile(p string, d []byte, mode os.FileMode) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	p = filepath.Clean(p)
+
+	if parentErr := m.checkParent(p); parentErr != nil {
+		return &os.PathError{Op: "open", Path: p, Err: parentErr}
+	}
+
+	data := make([]byte, len(d))
+	copy(data, d)
+
+	if file, ok := m.files[p]; ok {
+		if file.mode.IsDir() {
+			return &os.PathError{Op: "open", Path: p, Err: syscall.EISDIR}
+		}
+
+		file.data = data
+		file.modTime = time.Now()
+
+		return nil
+	}
+
+	m.files[p] = &memFile{data: data, mode: mode.Perm(), modTime: time.Now()}
+
+	return nil
+}
+
+func (m *memFileSystem) checkParent(p string) error {
+	parent := filepath.Dir(p)
+
+	if isMemRoot(parent) {
+		return nil
+	}
+
+	file, ok := m.files[parent]
+
+	if !ok {
+		return os.ErrNotExist
+	}
+
+	if !file.mode.IsDir() {
+		return syscall.ENOTDIR
+	}
+
+	return nil
+}
+
+func (m *memFileSystem) getChildren(p string) []string {
+	var children []string
+
+	for name := range m.files {
+		if name != p && filepath.Dir(name) == p {
+			children = append(children, name)
+		}
+	}
+
+	sort.Strings(children)
+
+	return children
+}
+
+func (m *memFileSystem) walk(p string, i os.FileInfo, f filepath.WalkFunc) error {
+	if walkErr := f(p, i, nil); walkErr != nil || !i.IsDir() {
+		return walkErr
+	}
+
+	m.mutex.RLock()
+	children := m.getChildren(p)
+	m.mutex.RUnlock()
+
+	for _, child := range children {
+		info, statErr := m.Stat(child)
+
+		if statErr != nil {
+			if walkErr := f(child, nil, statErr); walkErr != nil && walkErr != filepath.SkipDir {
+				return walkErr
+			}
+
+			continue
+		}
+
+		walkErr := m.walk(child, info, f)
+
+		if walkErr == filepath.SkipDir && info.IsDir() {
+			continue
+		}
+
+		if walkErr != nil {
+			return walkErr
+		}
+	}
+
+	return nil
+}
+
+func (i *memFileInfo) IsDir() bool {
+	return i.file.mode.IsDir()
+}
+
+func (i *memFileInfo) ModTime() time.Time {
+	return i.file.modTime
+}
+
+func (i *memFileInfo) Mode() os.FileMode {
+	return i.file.mode
+}
+
+func (i *memFileInfo) Name() string {
+	return i.name
+}
+
+func (i *memFileInfo) Size() int64 {
+	return int64(len(i.file.data))
+}
+
+func (i *memFileInfo) Sys() interface{} {
+	return nil
+}
+
+func isMemDescendant(p string, d string) bool {
+	if isMemRoot(d) {
+		return p != d
+	}
+
+	return strings.HasPrefix(p, d+string(filepath.Separator))
+}
+
+func isMemRoot(p string) bool {
+	return p == "." || p == filepath.Dir(p)
+}
@@ -0,0 +1,147 @@
+package fs_test
+
+import (
+	"github.com/sebuckler/teel/internal/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMemFileSystem(t *testing.T) {
+	for name, test := range getMemFileSystemTestCases() {
+		test(t, name)
+	}
+}
+
+func getMemFileSystemTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should write and read file":                 shouldWriteAndReadFile,
+		"should not alias written data":              shouldNotAliasWrittenData,
+		"should error writing without parent":        shouldErrorWritingWithoutParent,
+		"should create nested directories":           shouldCreateNestedDirectories,
+		"should error creating directory under file": shouldErrorCreatingDirectoryUnderFile,
+		"should error removing non empty directory":  shouldErrorRemovingNonEmptyDirectory,
+		"should remove directory tree":               shouldRemoveDirectoryTree,
+		"should rename directory with children":      shouldRenameDirectoryWithChildren,
+		"should walk files in lexical order":         shouldWalkFilesInLexicalOrder,
+	}
+}
+
+func shouldWriteAndReadFile(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	writeErr := memFs.WriteFile("config.json", []byte("{}"), 0644)
+	data, readErr := memFs.ReadFile("config.json")
+	info, statErr := memFs.Stat("config.json")
+
+	if writeErr != nil || readErr != nil || statErr != nil || string(data) != "{}" || info.Size() != 2 ||
+		info.Mode().Perm() != 0644 || info.IsDir() {
+		t.Fail()
+		t.Log(n + ": file not written and read back")
+	}
+}
+
+func shouldNotAliasWrittenData(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	written := []byte("abc")
+	_ = memFs.WriteFile("a.txt", written, 0644)
+	written[0] = 'x'
+	read, _ := memFs.ReadFile("a.txt")
+	read[1] = 'y'
+	data, _ := memFs.ReadFile("a.txt")
+
+	if string(data) != "abc" {
+		t.Fail()
+		t.Log(n + ": file data aliased caller buffers: " + string(data))
+	}
+}
+
+func shouldErrorWritingWithoutParent(t *testing.T, n string) {
+	writeErr := fs.NewMemFileSystem().WriteFile(filepath.Join("site", "config.json"), []byte{}, 0644)
+
+	if !os.IsNotExist(writeErr) {
+		t.Fail()
+		t.Log(n + ": wrote file without parent directory")
+	}
+}
+
+func shouldCreateNestedDirectories(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	mkdirErr := memFs.MkdirAll(filepath.Join("site", "server", "pages"), 0755)
+	info, statErr := memFs.Stat("site")
+
+	if mkdirErr != nil || statErr != nil || !info.IsDir() || info.Mode().Perm() != 0755 {
+		t.Fail()
+		t.Log(n + ": nested directories not created")
+	}
+}
+
+func shouldErrorCreatingDirectoryUnderFile(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	_ = memFs.WriteFile("site", []byte{}, 0644)
+
+	if mkdirErr := memFs.MkdirAll(filepath.Join("site", "server"), 0755); mkdirErr == nil {
+		t.Fail()
+		t.Log(n + ": created directory under a file")
+	}
+}
+
+func shouldErrorRemovingNonEmptyDirectory(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	_ = memFs.MkdirAll("site", 0755)
+	_ = memFs.WriteFile(filepath.Join("site", "config.json"), []byte{}, 0644)
+
+	if removeErr := memFs.Remove("site"); removeErr == nil {
+		t.Fail()
+		t.Log(n + ": removed non empty directory")
+	}
+}
+
+func shouldRemoveDirectoryTree(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	_ = memFs.MkdirAll(filepath.Join("site", "server"), 0755)
+	_ = memFs.WriteFile(filepath.Join("site", "server", "main.go"), []byte{}, 0644)
+	_ = memFs.WriteFile("sitemap.xml", []byte{}, 0644)
+	removeErr := memFs.RemoveAll("site")
+	_, siteErr := memFs.Stat(filepath.Join("site", "server"))
+	_, sitemapErr := memFs.Stat("sitemap.xml")
+
+	if removeErr != nil || !os.IsNotExist(siteErr) || sitemapErr != nil {
+		t.Fail()
+		t.Log(n + ": directory tree not removed")
+	}
+}
+
+func shouldRenameDirectoryWithChildren(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	_ = memFs.MkdirAll(filepath.Join("old", "server"), 0755)
+	_ = memFs.WriteFile(filepath.Join("old", "server", "main.go"), []byte("package main"), 0644)
+	renameErr := memFs.Rename("old", "new")
+	data, readErr := memFs.ReadFile(filepath.Join("new", "server", "main.go"))
+	_, oldErr := memFs.Stat("old")
+
+	if renameErr != nil || readErr != nil || string(data) != "package main" || !os.IsNotExist(oldErr) {
+		t.Fail()
+		t.Log(n + ": directory not renamed with its children")
+	}
+}
+
+func shouldWalkFilesInLexicalOrder(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	_ = memFs.MkdirAll(filepath.Join("site", "b"), 0755)
+	_ = memFs.WriteFile(filepath.Join("site", "c.txt"), []byte{}, 0644)
+	_ = memFs.WriteFile(filepath.Join("site", "a.txt"), []byte{}, 0644)
+	_ = memFs.WriteFile(filepath.Join("site", "b", "d.txt"), []byte{}, 0644)
+	var walked []string
+	walkErr := memFs.Walk("site", func(p string, i os.FileInfo, err error) error {
+		walked = append(walked, filepath.ToSlash(p))
+
+		return err
+	})
+	expected := "site,site/a.txt,site/b,site/b/d.txt,site/c.txt"
+
+	if walkErr != nil || strings.Join(walked, ",") != expected {
+		t.Fail()
+		t.Log(n + ": walked " + strings.Join(walked, ","))
+	}
+}
@@ -0,0 +1,379 @@
+package fs
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxAge     = 28 * 24 * time.Hour
+	DefaultMaxBackups = 5
+	DefaultMaxSize    = 10 * 1024 * 1024
+	backupTimeFormat  = "2006-01-02T15-04-05.000"
+	compressedSuffix  = ".gz"
+	lockSuffix        = ".lock"
+)
+
+type RotateConfig struct {
+	Compress       bool
+	MaxAge         time.Duration
+	MaxBackups     int
+	MaxSize        int64
+	OnCleanupError func(err error)
+}
+
+type rotatingFileWriter struct {
+	cleanup      sync.WaitGroup
+	cleanupMutex sync.Mutex
+	config       *RotateConfig
+	file         *os.File
+	lockFile     *os.File
+	mutex        sync.Mutex
+	path         string
+}
+
+type backupFile struct {
+	path string
+	time time.Time
+}
+
+func DefaultRotateConfig() *RotateConfig {
+	return &RotateConfig{
+		Compress:   true,
+		MaxAge:     DefaultMaxAge,
+		MaxBackups: DefaultMaxBackups,
+		MaxSize:    DefaultMaxSize,
+	}
+}
+
+func OpenRotatingFileWriter(p string, c *RotateConfig) (*rotatingFileWriter, error) {
+	if c == nil {
+		c = DefaultRotateConfig()
+	}
+
+	if mkdirErr := os.MkdirAll(filepath.Dir(p), 0755); mkdirErr != nil {
+		return nil, mkdirErr
+	}
+
+	lock, lockErr := os.OpenFile(p+lockSuffix, os.O_RDWR|os.O_CREATE, 0644)
+
+	if lockErr != nil {
+		return nil, lockErr
+	}
+
+	file, fileErr := openLogFile(p)
+
+	if fileErr != nil {
+		_ = lock.Close()
+
+		return nil, fileErr
+	}
+
+	return &rotatingFileWriter{
+		config:   c,
+		file:     file,
+		lockFile: lock,
+		path:     p,
+	}, nil
+}
+
+func (r *rotatingFileWriter) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.cleanup.Wait()
+	fileErr := r.file.Close()
+	lockErr := r.lockFile.Close()
+
+	if fileErr != nil {
+		return fileErr
+	}
+
+	return lockErr
+}
+
+func (r *rotatingFileWriter) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if lockErr := lockFile(r.lockFile); lockErr != nil {
+		return 0, lockErr
+	}
+
+	defer func() {
+		_ = unlockFile(r.lockFile)
+	}()
+
+	if reopenErr := r.reopenIfRotated(); reopenErr != nil {
+		return 0, reopenErr
+	}
+
+	if r.config.MaxSize > 0 {
+		info, statErr := r.file.Stat()
+
+		if statErr != nil {
+			return 0, statErr
+		}
+
+		if info.Size() > 0 && info.Size()+int64(len(p)) > r.config.MaxSize {
+			if rotateErr := r.rotate(); rotateErr != nil {
+				return 0, rotateErr
+			}
+		}
+	}
+
+	return r.file.Write(p)
+}
+
+func (r *rotatingFileWriter) reopenIfRotated() error {
+	current, currentErr := r.file.Stat()
+
+	if currentErr != nil {
+		return currentErr
+	}
+
+	latest, latestErr := os.Stat(r.path)
+
+	if latestErr == nil && os.SameFile(current, latest) {
+		return nil
+	}
+
+	if latestErr != nil && !os.IsNotExist(latestErr) {
+		return latestErr
+	}
+
+	file, fileErr := openLogFile(r.path)
+
+	if fileErr != nil {
+		return fileErr
+	}
+
+	_ = r.file.Close()
+	r.file = file
+
+	return nil
+}
+
+func (r *rotatingFileWriter) rotate() error {
+	backupTime := time.Now()
+	backups, backupsErr := getBackupFiles(r.path)
+
+	if backupsErr != nil {
+		return backupsErr
+	}
+
+	if len(backups) > 0 && !backupTime.After(backups[0].time) {
+		backupTime = backups[0].time.Add(time.Millisecond)
+	}
+
+	backupPath := getBackupPath(r.path, backupTime)
+
+	if closeErr := r.file.Close(); closeErr != nil {
+		return closeErr
+	}
+
+	if renameErr := os.Rename(r.path, backupPath); renameErr != nil {
+		return renameErr
+	}
+
+	file, fileErr := openLogFile(r.path)
+
+	if fileErr != nil {
+		return fileErr
+	}
+
+	r.file = file
+	r.cleanup.Add(1)
+
+	go r.cleanupBackups()
+
+	return nil
+}
+
+func (r *rotatingFileWriter) cleanupBackups() {
+	defer r.cleanup.Done()
+
+	r.cleanupMutex.Lock()
+	defer r.cleanupMutex.Unlock()
+
+	if r.config.Compress {
+		if compressErr := r.compressBackups(); compressErr != nil {
+			r.reportCleanupError(compressErr)
+		}
+	}
+
+	if removeErr := r.removeOldBackups(); removeErr != nil {
+		r.reportCleanupError(removeErr)
+	}
+}
+
+func (r *rotatingFileWriter) compressBackups() error {
+	backups, backupsErr := getBackupFiles(r.path)
+
+	if backupsErr != nil {
+		return backupsErr
+	}
+
+	for _, backup := range backups {
+		if strings.HasSuffix(backup.path, compressedSuffix) {
+			continue
+		}
+
+		if compressErr := compressFile(backup.path); compressErr != nil {
+			return compressErr
+		}
+	}
+
+	return nil
+}
+
+func (r *rotatingFileWriter) removeOldBackups() error {
+	backups, backupsErr := getBackupFiles(r.path)
+
+	if backupsErr != nil {
+		return backupsErr
+	}
+
+	cutoff := time.Now().Add(-r.config.MaxAge)
+
+	for i, backup := range backups {
+		expired := r.config.MaxAge > 0 && backup.time.Before(cutoff)
+		excess := r.config.MaxBackups > 0 && i >= r.config.MaxBackups
+
+		if !expired && !excess {
+			continue
+		}
+
+		if removeErr := os.Remove(backup.path); removeErr != nil && !os.IsNotExist(removeErr) {
+			return removeErr
+		}
+	}
+
+	return nil
+}
+
+func (r *rotatingFileWriter) reportCleanupError(err error) {
+	if r.config.OnCleanupError != nil {
+		r.config.OnCleanupError(err)
+	}
+}
+
+func compressFile(p string) error {
+	source, sourceErr := os.Open(p)
+
+	if os.IsNotExist(sourceErr) {
+		return nil
+	}
+
+	if sourceErr != nil {
+		return sourceErr
+	}
+
+	defer source.Close()
+
+	target, targetErr := OpenAtomicFileWriter(p+compressedSuffix, 0644)
+
+	if targetErr != nil {
+		return targetErr
+	}
+
+	writer := gzip.NewWriter(target)
+
+	if _, copyErr := io.Copy(writer, source); copyErr != nil {
+		_ = target.Abort()
+
+		return copyErr
+	}
+
+	if gzipErr := writer.Close(); gzipErr != nil {
+		_ = target.Abort()
+
+		return gzipErr
+	}
+
+	if closeErr := target.Close(); closeErr != nil {
+		return closeErr
+	}
+
+	if removeErr := os.Remove(p); removeErr != nil && !os.IsNotExist(removeErr) {
+		return removeErr
+	}
+
+	return nil
+}
+
+func getBackupFiles(p string) ([]*backupFile, error) {
+	prefix, ext := splitLogPath(filepath.Base(p))
+	entries, readErr := ioutil.ReadDir(filepath.Dir(p))
+
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	var backups []*backupFile
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if !strings.HasPrefix(name, prefix+"-") {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix+"-"), compressedSuffix)
+
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+
+		backupTime, parseErr := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, ext))
+
+		if parseErr != nil {
+			continue
+		}
+
+		backups = append(backups, &backupFile{path: filepath.Join(filepath.Dir(p), name), time: backupTime})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+
+	return backups, nil
+}
+
+func getBackupPath(p string, t time.Time) string {
+	prefix, ext := splitLogPath(p)
+
+	for {
+		backupPath := prefix + "-" + t.UTC().Format(backupTimeFormat) + ext
+
+		if !fileExists(backupPath) && !fileExists(backupPath+compressedSuffix) {
+			return backupPath
+		}
+
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func fileExists(p string) bool {
+	_, statErr := os.Stat(p)
+
+	return statErr == nil
+}
+
+func openLogFile(p string) (*os.File, error) {
+	return os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+}
+
+func splitLogPath(p string) (string, string) {
+	ext := filepath.Ext(p)
+
+	return strings.TrimSuffix(p, ext), ext
+}
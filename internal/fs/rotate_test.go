@@ -0,0 +1,322 @@
+package fs_test
+
+import (
+	"compress/gzip"
+	"github.com/sebuckler/teel/internal/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileWriter(t *testing.T) {
+	for name, test := range getRotatingFileWriterTestCases() {
+		test(t, name)
+	}
+}
+
+func getRotatingFileWriterTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should rotate when max size exceeded":        shouldRotateWhenMaxSizeExceeded,
+		"should prune backups beyond max backups":     shouldPruneBackupsBeyondMaxBackups,
+		"should prune backups older than max age":     shouldPruneBackupsOlderThanMaxAge,
+		"should compress rotated backups":             shouldCompressRotatedBackups,
+		"should report cleanup errors without fail":   shouldReportCleanupErrorsWithoutFail,
+		"should create parent directory of log file":  shouldCreateParentDirectoryOfLogFile,
+		"should prune backups in glob-like directory": shouldPruneBackupsInGlobLikeDirectory,
+		"should compress leftover backups":            shouldCompressLeftoverBackups,
+	}
+}
+
+func makeTempDir(t *testing.T) string {
+	dir, dirErr := ioutil.TempDir("", "teel-fs")
+
+	if dirErr != nil {
+		t.Fatal(dirErr)
+	}
+
+	return dir
+}
+
+func getBackups(t *testing.T, d string) []string {
+	matches, globErr := filepath.Glob(filepath.Join(d, "teel-*.log*"))
+
+	if globErr != nil {
+		t.Fatal(globErr)
+	}
+
+	sort.Strings(matches)
+
+	return matches
+}
+
+func writeEntries(t *testing.T, w interface{ Write([]byte) (int, error) }, e ...string) {
+	for _, entry := range e {
+		if _, writeErr := w.Write([]byte(entry)); writeErr != nil {
+			t.Fatal(writeErr)
+		}
+	}
+}
+
+func closeWriter(t *testing.T, w interface{ Close() error }) {
+	if closeErr := w.Close(); closeErr != nil {
+		t.Fatal(closeErr)
+	}
+}
+
+func shouldRotateWhenMaxSizeExceeded(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "teel.log")
+	writer, openErr := fs.OpenRotatingFileWriter(p, &fs.RotateConfig{MaxSize: 10})
+
+	if openErr != nil {
+		t.Fatal(openErr)
+	}
+
+	defer writer.Close()
+
+	writeEntries(t, writer, "first\n", "second\n")
+	current, _ := ioutil.ReadFile(p)
+	backups := getBackups(t, dir)
+
+	if string(current) != "second\n" || len(backups) != 1 {
+		t.Fail()
+		t.Log(n + ": log file contains '" + string(current) + "' after rotation")
+
+		return
+	}
+
+	if backup, _ := ioutil.ReadFile(backups[0]); string(backup) != "first\n" {
+		t.Fail()
+		t.Log(n + ": backup contains '" + string(backup) + "'")
+	}
+}
+
+func shouldPruneBackupsBeyondMaxBackups(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "teel.log")
+	writer, openErr := fs.OpenRotatingFileWriter(p, &fs.RotateConfig{MaxBackups: 2, MaxSize: 4})
+
+	if openErr != nil {
+		t.Fatal(openErr)
+	}
+
+	writeEntries(t, writer, "one\n", "two\n", "three\n", "four\n", "five\n")
+	closeWriter(t, writer)
+	backups := getBackups(t, dir)
+
+	if len(backups) != 2 {
+		t.Fail()
+		t.Logf("%s: kept %d backups instead of 2", n, len(backups))
+
+		return
+	}
+
+	older, _ := ioutil.ReadFile(backups[0])
+	newer, _ := ioutil.ReadFile(backups[1])
+
+	if string(older) != "three\n" || string(newer) != "four\n" {
+		t.Fail()
+		t.Log(n + ": kept backups '" + string(older) + "' and '" + string(newer) + "'")
+	}
+}
+
+func shouldPruneBackupsOlderThanMaxAge(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	expired := filepath.Join(dir, "teel-2000-01-01T00-00-00.000.log.gz")
+	unrelated := filepath.Join(dir, "teel-notes.log")
+
+	for _, f := range []string{expired, unrelated} {
+		if writeErr := ioutil.WriteFile(f, []byte("old\n"), 0644); writeErr != nil {
+			t.Fatal(writeErr)
+		}
+	}
+
+	writer, openErr := fs.OpenRotatingFileWriter(filepath.Join(dir, "teel.log"), &fs.RotateConfig{MaxAge: time.Hour, MaxSize: 4})
+
+	if openErr != nil {
+		t.Fatal(openErr)
+	}
+
+	writeEntries(t, writer, "one\n", "two\n")
+	closeWriter(t, writer)
+
+	if _, statErr := os.Stat(expired); !os.IsNotExist(statErr) {
+		t.Fail()
+		t.Log(n + ": expired backup not removed")
+	}
+
+	if _, statErr := os.Stat(unrelated); statErr != nil {
+		t.Fail()
+		t.Log(n + ": unrelated file removed")
+	}
+}
+
+func shouldCompressRotatedBackups(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	writer, openErr := fs.OpenRotatingFileWriter(filepath.Join(dir, "teel.log"), &fs.RotateConfig{Compress: true, MaxSize: 4})
+
+	if openErr != nil {
+		t.Fatal(openErr)
+	}
+
+	writeEntries(t, writer, "one\n", "two\n")
+	closeWriter(t, writer)
+	backups := getBackups(t, dir)
+
+	if len(backups) != 1 || filepath.Ext(backups[0]) != ".gz" {
+		t.Fail()
+		t.Logf("%s: found backups %v instead of one compressed backup", n, backups)
+
+		return
+	}
+
+	file, fileErr := os.Open(backups[0])
+
+	if fileErr != nil {
+		t.Fatal(fileErr)
+	}
+
+	defer file.Close()
+
+	reader, readerErr := gzip.NewReader(file)
+
+	if readerErr != nil {
+		t.Fatal(readerErr)
+	}
+
+	if content, _ := ioutil.ReadAll(reader); string(content) != "one\n" {
+		t.Fail()
+		t.Log(n + ": compressed backup contains '" + string(content) + "'")
+	}
+}
+
+func shouldReportCleanupErrorsWithoutFail(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	expired := filepath.Join(dir, "teel-2000-01-01T00-00-00.000.log")
+
+	if mkdirErr := os.MkdirAll(filepath.Join(expired, "child"), 0755); mkdirErr != nil {
+		t.Fatal(mkdirErr)
+	}
+
+	var cleanupErr error
+	p := filepath.Join(dir, "teel.log")
+	writer, openErr := fs.OpenRotatingFileWriter(p, &fs.RotateConfig{
+		MaxAge:         time.Hour,
+		MaxSize:        4,
+		OnCleanupError: func(err error) { cleanupErr = err },
+	})
+
+	if openErr != nil {
+		t.Fatal(openErr)
+	}
+
+	writeEntries(t, writer, "one\n", "two\n")
+	closeWriter(t, writer)
+
+	if current, _ := ioutil.ReadFile(p); cleanupErr == nil || string(current) != "two\n" {
+		t.Fail()
+		t.Log(n + ": cleanup error not reported or write failed")
+	}
+}
+
+func shouldCreateParentDirectoryOfLogFile(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "state", "logs", "teel.log")
+	writer, openErr := fs.OpenRotatingFileWriter(p, nil)
+
+	if openErr != nil {
+		t.Fail()
+		t.Log(n + ": " + openErr.Error())
+
+		return
+	}
+
+	defer writer.Close()
+
+	writeEntries(t, writer, "entry\n")
+
+	if current, _ := ioutil.ReadFile(p); string(current) != "entry\n" {
+		t.Fail()
+		t.Log(n + ": log file contains '" + string(current) + "'")
+	}
+}
+
+func shouldPruneBackupsInGlobLikeDirectory(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	logDir := filepath.Join(dir, "logs[1]")
+	writer, openErr := fs.OpenRotatingFileWriter(filepath.Join(logDir, "teel.log"), &fs.RotateConfig{MaxBackups: 1, MaxSize: 4})
+
+	if openErr != nil {
+		t.Fatal(openErr)
+	}
+
+	writeEntries(t, writer, "one\n", "two\n", "three\n")
+	closeWriter(t, writer)
+	entries, readErr := ioutil.ReadDir(logDir)
+
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+
+	var backups []string
+
+	for _, entry := range entries {
+		if entry.Name() != "teel.log" && entry.Name() != "teel.log.lock" {
+			backups = append(backups, entry.Name())
+		}
+	}
+
+	if len(backups) != 1 {
+		t.Fail()
+		t.Logf("%s: kept backups %v instead of 1", n, backups)
+	}
+}
+
+func shouldCompressLeftoverBackups(t *testing.T, n string) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	leftover := filepath.Join(dir, "teel-2000-01-01T00-00-00.000.log")
+
+	if writeErr := ioutil.WriteFile(leftover, []byte("old\n"), 0644); writeErr != nil {
+		t.Fatal(writeErr)
+	}
+
+	writer, openErr := fs.OpenRotatingFileWriter(filepath.Join(dir, "teel.log"), &fs.RotateConfig{Compress: true, MaxSize: 4})
+
+	if openErr != nil {
+		t.Fatal(openErr)
+	}
+
+	writeEntries(t, writer, "one\n", "two\n")
+	closeWriter(t, writer)
+
+	for _, backup := range getBackups(t, dir) {
+		if filepath.Ext(backup) != ".gz" {
+			t.Fail()
+			t.Log(n + ": backup " + backup + " not compressed")
+		}
+	}
+
+	if _, statErr := os.Stat(leftover + ".gz"); statErr != nil {
+		t.Fail()
+		t.Log(n + ": leftover backup not compressed")
+	}
+}
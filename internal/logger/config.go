@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sebuckler/teel/internal/fs"
+	"io"
+	"time"
+)
+
+type Config struct {
+	File          string
+	FileFormat    string
+	FileLevel     string
+	FlushInterval string
+	FlushSize     int
+	Format        string
+	Level         string
+	MaxAge        int
+	MaxBackups    int
+	MaxSize       int
+	NoCompress    bool
+	Quiet         bool
+	Verbose       bool
+}
+
+const (
+	bytesPerMegabyte = 1024 * 1024
+	hoursPerDay      = 24
+)
+
+func (c *Config) GetBufferConfig() (*fs.BufferConfig, error) {
+	buffer := fs.DefaultBufferConfig()
+
+	if c.FlushInterval != "" {
+		interval, parseErr := time.ParseDuration(c.FlushInterval)
+
+		if parseErr != nil || interval < 0 {
+			return nil, fmt.Errorf("invalid log flush interval: %s", c.FlushInterval)
+		}
+
+		buffer.FlushInterval = interval
+	}
+
+	if c.FlushSize < 0 {
+		return nil, errors.New("log flush size cannot be negative")
+	}
+
+	if c.FlushSize > 0 {
+		buffer.FlushSize = c.FlushSize
+	}
+
+	return buffer, nil
+}
+
+func (c *Config) GetFileLevel() (Level, error) {
+	if c.FileLevel != "" {
+		return ParseLevel(c.FileLevel)
+	}
+
+	return DefaultFileLevel, nil
+}
+
+func (c *Config) GetLevel() (Level, error) {
+	if c.Verbose && c.Quiet {
+		return DefaultLevel, errors.New("verbose and quiet cannot be used together")
+	}
+
+	if c.Level != "" {
+		return ParseLevel(c.Level)
+	}
+
+	if c.Verbose {
+		return LevelDebug, nil
+	}
+
+	if c.Quiet {
+		return LevelError, nil
+	}
+
+	return DefaultLevel, nil
+}
+
+func (c *Config) GetRotateConfig() (*fs.RotateConfig, error) {
+	if c.MaxAge < 0 || c.MaxBackups < 0 || c.MaxSize < 0 {
+		return nil, errors.New("log rotation limits cannot be negative")
+	}
+
+	rotate := fs.DefaultRotateConfig()
+	rotate.Compress = !c.NoCompress
+
+	if c.MaxAge > 0 {
+		rotate.MaxAge = time.Duration(c.MaxAge) * hoursPerDay * time.Hour
+	}
+
+	if c.MaxBackups > 0 {
+		rotate.MaxBackups = c.MaxBackups
+	}
+
+	if c.MaxSize > 0 {
+		rotate.MaxSize = int64(c.MaxSize) * bytesPerMegabyte
+	}
+
+	return rotate, nil
+}
+
+func (c *Config) NewSinks(w io.Writer) ([]*Sink, error) {
+	level, levelErr := c.GetLevel()
+
+	if levelErr != nil {
+		return nil, levelErr
+	}
+
+	formatter, formatErr := NewFormatter(c.Format)
+
+	if formatErr != nil {
+		return nil, formatErr
+	}
+
+	console := NewConsoleSink(w, level, formatter)
+	sinks := []*Sink{console}
+
+	if c.File == "" {
+		return sinks, nil
+	}
+
+	fileLevel, fileLevelErr := c.GetFileLevel()
+
+	if fileLevelErr != nil {
+		return nil, fileLevelErr
+	}
+
+	fileFormat := c.FileFormat
+
+	if fileFormat == "" {
+		fileFormat = FormatJSON
+	}
+
+	fileFormatter, fileFormatErr := NewFormatter(fileFormat)
+
+	if fileFormatErr != nil {
+		return nil, fileFormatErr
+	}
+
+	rotate, rotateErr := c.GetRotateConfig()
+
+	if rotateErr != nil {
+		return nil, rotateErr
+	}
+
+	rotate.OnCleanupError = func(err error) {
+		console.write(&Entry{Level: LevelWarn, Message: "failed to clean up rotated logs: " + err.Error(), Time: time.Now()})
+	}
+
+	buffer, bufferErr := c.GetBufferConfig()
+
+	if bufferErr != nil {
+		return nil, bufferErr
+	}
+
+	fileSink, fileErr := OpenFileSink(c.File, rotate, buffer, fileLevel, fileFormatter)
+
+	if fileErr != nil {
+		return nil, fileErr
+	}
+
+	return append(sinks, fileSink), nil
+}
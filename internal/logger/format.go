@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+const CommandKey = "command"
+
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+type Entry struct {
+	Fields  []Field
+	Level   Level
+	Message string
+	Time    time.Time
+}
+
+type Formatter interface {
+	Format(e *Entry) ([]byte, error)
+}
+
+type jsonFormatter struct{}
+
+type textFormatter struct{}
+
+func F(k string, v interface{}) Field {
+	return Field{Key: k, Value: v}
+}
+
+func FormatNames() []string {
+	return []string{FormatText, FormatJSON}
+}
+
+func NewFormatter(s string) (Formatter, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", FormatText:
+		return NewTextFormatter(), nil
+	case FormatJSON:
+		return NewJSONFormatter(), nil
+	default:
+		return nil, fmt.Errorf("invalid log format: %s", s)
+	}
+}
+
+func NewJSONFormatter() Formatter {
+	return &jsonFormatter{}
+}
+
+func NewTextFormatter() Formatter {
+	return &textFormatter{}
+}
+
+func (j *jsonFormatter) Format(e *Entry) ([]byte, error) {
+	var buffer bytes.Buffer
+	fields := append([]Field{
+		F("time", e.Time.Format(time.RFC3339Nano)),
+		F("level", e.Level.String()),
+		F("message", e.Message),
+	}, e.Fields...)
+
+	buffer.WriteByte('{')
+
+	for i, field := range fields {
+		key, keyErr := json.Marshal(field.Key)
+
+		if keyErr != nil {
+			return nil, keyErr
+		}
+
+		value, valueErr := json.Marshal(getFieldValue(field.Value))
+
+		if valueErr != nil {
+			value, _ = json.Marshal(fmt.Sprint(field.Value))
+		}
+
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+
+	buffer.WriteString("}\n")
+
+	return buffer.Bytes(), nil
+}
+
+func (t *textFormatter) Format(e *Entry) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteString(e.Time.Format("2006/01/02 15:04:05") + " " + e.Level.prefix() + e.Message)
+
+	for _, field := range e.Fields {
+		buffer.WriteString(" " + field.Key + "=" + quoteTextValue(fmt.Sprint(getFieldValue(field.Value))))
+	}
+
+	buffer.WriteByte('\n')
+
+	return buffer.Bytes(), nil
+}
+
+func getFieldValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case error:
+		return value.Error()
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return v
+	}
+}
+
+func mergeFields(f []Field, n []Field) []Field {
+	merged := make([]Field, 0, len(f)+len(n))
+	merged = append(merged, f...)
+
+	for _, field := range n {
+		replaced := false
+
+		for i := range merged {
+			if merged[i].Key == field.Key {
+				merged[i] = field
+				replaced = true
+
+				break
+			}
+		}
+
+		if !replaced {
+			merged = append(merged, field)
+		}
+	}
+
+	return merged
+}
+
+func quoteTextValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
@@ -0,0 +1,97 @@
+package logger_test
+
+import (
+	"errors"
+	"github.com/sebuckler/teel/internal/logger"
+	"testing"
+	"time"
+)
+
+func TestFormatter_Format(t *testing.T) {
+	for name, test := range getFormatTestCases() {
+		test(t, name)
+	}
+}
+
+func getFormatTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should format text entries":          shouldFormatTextEntries,
+		"should format json entries":          shouldFormatJSONEntries,
+		"should error on unknown format name": shouldErrorOnUnknownFormatName,
+	}
+}
+
+func newFormatTestEntry(f ...logger.Field) *logger.Entry {
+	return &logger.Entry{
+		Fields:  f,
+		Level:   logger.LevelWarn,
+		Message: "disk almost full",
+		Time:    time.Date(2020, 5, 17, 21, 25, 48, 500000000, time.UTC),
+	}
+}
+
+func shouldFormatTextEntries(t *testing.T, n string) {
+	testCases := map[string]*logger.Entry{
+		"2020/05/17 21:25:48 Warn: disk almost full\n": newFormatTestEntry(),
+		"2020/05/17 21:25:48 Warn: disk almost full command=\"teel new\" free=12 err=\"no space\"\n": newFormatTestEntry(
+			logger.F(logger.CommandKey, "teel new"),
+			logger.F("free", 12),
+			logger.F("err", errors.New("no space")),
+		),
+		"2020/05/17 21:25:48 Warn: disk almost full path=\"\" quote=\"a\\\"b\"\n": newFormatTestEntry(
+			logger.F("path", ""),
+			logger.F("quote", `a"b`),
+		),
+	}
+
+	for expected, entry := range testCases {
+		formatted, formatErr := logger.NewTextFormatter().Format(entry)
+
+		if formatErr != nil || string(formatted) != expected {
+			t.Fail()
+			t.Log(n + ": formatted '" + string(formatted) + "' instead of '" + expected + "'")
+		}
+	}
+}
+
+func shouldFormatJSONEntries(t *testing.T, n string) {
+	testCases := map[string]*logger.Entry{
+		`{"time":"2020-05-17T21:25:48.5Z","level":"warn","message":"disk almost full"}` + "\n": newFormatTestEntry(),
+		`{"time":"2020-05-17T21:25:48.5Z","level":"warn","message":"disk almost full","command":"teel new",` +
+			`"free":12,"err":"no space","level":"info"}` + "\n": newFormatTestEntry(
+			logger.F(logger.CommandKey, "teel new"),
+			logger.F("free", 12),
+			logger.F("err", errors.New("no space")),
+			logger.F("level", logger.LevelInfo),
+		),
+		`{"time":"2020-05-17T21:25:48.5Z","level":"warn","message":"disk almost full","fn":"0x0"}` + "\n": newFormatTestEntry(
+			logger.F("fn", unmarshalable{}),
+		),
+	}
+
+	for expected, entry := range testCases {
+		formatted, formatErr := logger.NewJSONFormatter().Format(entry)
+
+		if formatErr != nil || string(formatted) != expected {
+			t.Fail()
+			t.Log(n + ": formatted '" + string(formatted) + "' instead of '" + expected + "'")
+		}
+	}
+}
+
+func shouldErrorOnUnknownFormatName(t *testing.T, n string) {
+	if _, formatErr := logger.NewFormatter("xml"); formatErr == nil {
+		t.Fail()
+		t.Log(n + ": created formatter for unknown format")
+	}
+}
+
+type unmarshalable struct{}
+
+func (u unmarshalable) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("cannot marshal")
+}
+
+func (u unmarshalable) String() string {
+	return "0x0"
+}
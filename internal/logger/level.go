@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+const (
+	DefaultLevel     = LevelWarn
+	DefaultFileLevel = LevelDebug
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+func LevelNames() []string {
+	return []string{"debug", "info", "warn", "error"}
+}
+
+func ParseLevel(s string) (Level, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+
+	if name == "warning" {
+		return LevelWarn, nil
+	}
+
+	for level, levelName := range levelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+
+	return DefaultLevel, fmt.Errorf("invalid log level: %s", s)
+}
+
+func (v Level) String() string {
+	if name, ok := levelNames[v]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("level(%d)", int(v))
+}
+
+func (v Level) prefix() string {
+	switch v {
+	case LevelDebug:
+		return "Debug: "
+	case LevelInfo:
+		return "Info: "
+	case LevelWarn:
+		return "Warn: "
+	default:
+		return "Error: "
+	}
+}
@@ -1,45 +1,173 @@
 package logger
 
 import (
-	"io"
-	"log"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 )
 
 type Logger interface {
+	Close() error
+	Debug(v ...interface{})
+	Debugf(f string, v ...interface{})
 	Error(v ...interface{})
 	Errorf(f string, v ...interface{})
-	Log(v ...interface{})
-	Logf(f string, v ...interface{})
+	Flush() error
+	Info(v ...interface{})
+	Infof(f string, v ...interface{})
+	Redact(v ...string)
+	RedactText(s string) string
+	SetSinks(s ...*Sink) error
+	Warn(v ...interface{})
+	Warnf(f string, v ...interface{})
+	With(f ...Field) Logger
+	WithoutConsole() Logger
 }
 
 type logger struct {
-	errLogger *log.Logger
-	outLogger *log.Logger
+	core        *loggerCore
+	fields      []Field
+	skipConsole bool
 }
 
-func New(o io.Writer, e io.Writer) Logger {
-	if e == nil {
-		e = o
-	}
+type loggerCore struct {
+	mutex    sync.Mutex
+	redactor *redactor
+	sinks    []*Sink
+}
 
+func New(s ...*Sink) Logger {
 	return &logger{
-		errLogger: log.New(e, "Error: ", log.LstdFlags),
-		outLogger: log.New(o, "Log: ", log.LstdFlags),
+		core: &loggerCore{
+			redactor: &redactor{},
+			sinks:    s,
+		},
 	}
 }
 
+func (l *logger) Close() error {
+	l.core.mutex.Lock()
+	defer l.core.mutex.Unlock()
+
+	return l.core.close()
+}
+
+func (l *logger) Debug(v ...interface{}) {
+	l.write(LevelDebug, fmt.Sprintln(v...))
+}
+
+func (l *logger) Debugf(f string, v ...interface{}) {
+	l.write(LevelDebug, fmt.Sprintf(f, v...))
+}
+
 func (l *logger) Error(v ...interface{}) {
-	l.errLogger.Println(v...)
+	l.write(LevelError, fmt.Sprintln(v...))
 }
 
 func (l *logger) Errorf(f string, v ...interface{}) {
-	l.errLogger.Printf(f, v...)
+	l.write(LevelError, fmt.Sprintf(f, v...))
 }
 
-func (l *logger) Log(v ...interface{}) {
-	l.outLogger.Println(v...)
+func (l *logger) Flush() error {
+	l.core.mutex.Lock()
+	defer l.core.mutex.Unlock()
+
+	var flushErr error
+
+	for _, sink := range l.core.sinks {
+		if err := sink.flush(); err != nil && flushErr == nil {
+			flushErr = err
+		}
+	}
+
+	return flushErr
 }
 
-func (l *logger) Logf(f string, v ...interface{}) {
-	l.outLogger.Printf(f, v...)
+func (l *logger) Info(v ...interface{}) {
+	l.write(LevelInfo, fmt.Sprintln(v...))
+}
+
+func (l *logger) Infof(f string, v ...interface{}) {
+	l.write(LevelInfo, fmt.Sprintf(f, v...))
+}
+
+func (l *logger) Redact(v ...string) {
+	l.core.mutex.Lock()
+	defer l.core.mutex.Unlock()
+
+	l.core.redactor.add(v...)
+}
+
+func (l *logger) RedactText(s string) string {
+	l.core.mutex.Lock()
+	defer l.core.mutex.Unlock()
+
+	return l.core.redactor.redact(s)
+}
+
+func (l *logger) SetSinks(s ...*Sink) error {
+	l.core.mutex.Lock()
+	defer l.core.mutex.Unlock()
+
+	closeErr := l.core.close()
+	l.core.sinks = s
+
+	return closeErr
+}
+
+func (l *logger) Warn(v ...interface{}) {
+	l.write(LevelWarn, fmt.Sprintln(v...))
+}
+
+func (l *logger) Warnf(f string, v ...interface{}) {
+	l.write(LevelWarn, fmt.Sprintf(f, v...))
+}
+
+func (l *logger) With(f ...Field) Logger {
+	return &logger{
+		core:        l.core,
+		fields:      mergeFields(l.fields, f),
+		skipConsole: l.skipConsole,
+	}
+}
+
+func (l *logger) WithoutConsole() Logger {
+	return &logger{
+		core:        l.core,
+		fields:      l.fields,
+		skipConsole: true,
+	}
+}
+
+func (l *logger) write(v Level, m string) {
+	l.core.mutex.Lock()
+	defer l.core.mutex.Unlock()
+
+	entry := &Entry{
+		Fields:  l.core.redactor.redactFields(l.fields),
+		Level:   v,
+		Message: l.core.redactor.redact(strings.TrimRight(m, "\n")),
+		Time:    time.Now(),
+	}
+
+	for _, sink := range l.core.sinks {
+		if l.skipConsole && sink.Console {
+			continue
+		}
+
+		sink.write(entry)
+	}
+}
+
+func (c *loggerCore) close() error {
+	var closeErr error
+
+	for _, sink := range c.sinks {
+		if err := sink.close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}
+
+	return closeErr
 }
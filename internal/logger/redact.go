@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	RedactedText       = "********"
+	minRedactValueSize = 4
+)
+
+var redactPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(\bbearer\s+)[a-z0-9\-._~+/]+=*`),
+	regexp.MustCompile(`(?i)(\b(?:api[_-]?key|access[_-]?key|auth|password|passwd|secret|token)["']?\s*[:=]\s*["']?)[^\s"'&,;)\]}]+`),
+	regexp.MustCompile(`(\b)(?:gh[opsur]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`),
+	regexp.MustCompile(`(\b)(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`(\b)xox[abeprs]-[A-Za-z0-9-]{10,}`),
+	regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+(@)`),
+}
+
+type redactor struct {
+	values []string
+}
+
+func (r *redactor) add(v ...string) {
+	for _, value := range v {
+		if value == "" {
+			continue
+		}
+
+		exists := false
+
+		for _, registered := range r.values {
+			if registered == value {
+				exists = true
+
+				break
+			}
+		}
+
+		if !exists {
+			r.values = append(r.values, value)
+		}
+	}
+}
+
+func (r *redactor) redact(s string) string {
+	for _, value := range r.values {
+		if len(value) >= minRedactValueSize {
+			s = strings.ReplaceAll(s, value, RedactedText)
+		}
+	}
+
+	for _, value := range r.values {
+		if len(value) < minRedactValueSize {
+			s = redactWord(s, value)
+		}
+	}
+
+	for _, pattern := range redactPatterns {
+		replacement := "${1}" + RedactedText
+
+		if pattern.NumSubexp() > 1 {
+			replacement += "${2}"
+		}
+
+		s = pattern.ReplaceAllString(s, replacement)
+	}
+
+	return s
+}
+
+func (r *redactor) redactFields(f []Field) []Field {
+	if len(f) == 0 {
+		return f
+	}
+
+	redacted := make([]Field, len(f))
+
+	for i, field := range f {
+		redacted[i] = field
+
+		if value, ok := getFieldValue(field.Value).(string); ok {
+			redacted[i].Value = r.redact(value)
+		}
+	}
+
+	return redacted
+}
+
+func isWordBoundary(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return true
+	}
+
+	c := s[i]
+
+	return c != '_' && (c < '0' || c > '9') && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z')
+}
+
+func redactWord(s string, v string) string {
+	var redacted strings.Builder
+	start := 0
+	offset := 0
+
+	for {
+		index := strings.Index(s[offset:], v)
+
+		if index < 0 {
+			break
+		}
+
+		index += offset
+		end := index + len(v)
+
+		if isWordBoundary(s, index-1) && isWordBoundary(s, end) {
+			redacted.WriteString(s[start:index] + RedactedText)
+			start = end
+			offset = end
+		} else {
+			offset = index + 1
+		}
+	}
+
+	return redacted.String() + s[start:]
+}
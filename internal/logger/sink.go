@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"github.com/sebuckler/teel/internal/fs"
+	"io"
+)
+
+type flusher interface {
+	Flush() error
+}
+
+type Sink struct {
+	closer    io.Closer
+	Console   bool
+	Formatter Formatter
+	Level     Level
+	Writer    io.Writer
+}
+
+func NewSink(w io.Writer, l Level, f Formatter) *Sink {
+	if f == nil {
+		f = NewTextFormatter()
+	}
+
+	return &Sink{
+		Formatter: f,
+		Level:     l,
+		Writer:    w,
+	}
+}
+
+func NewConsoleSink(w io.Writer, l Level, f Formatter) *Sink {
+	sink := NewSink(w, l, f)
+	sink.Console = true
+
+	return sink
+}
+
+func OpenFileSink(p string, r *fs.RotateConfig, b *fs.BufferConfig, l Level, f Formatter) (*Sink, error) {
+	file, fileErr := fs.OpenRotatingFileWriter(p, r)
+
+	if fileErr != nil {
+		return nil, fileErr
+	}
+
+	writer := fs.NewBufferedWriter(file, b)
+	sink := NewSink(writer, l, f)
+	sink.closer = writer
+
+	return sink, nil
+}
+
+func (s *Sink) close() error {
+	if s.closer == nil {
+		return nil
+	}
+
+	closeErr := s.closer.Close()
+	s.closer = nil
+
+	return closeErr
+}
+
+func (s *Sink) flush() error {
+	if f, ok := s.Writer.(flusher); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+func (s *Sink) write(e *Entry) {
+	if e.Level < s.Level {
+		return
+	}
+
+	entry, formatErr := s.Formatter.Format(e)
+
+	if formatErr != nil {
+		return
+	}
+
+	_, _ = s.Writer.Write(entry)
+}
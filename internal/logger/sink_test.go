@@ -0,0 +1,158 @@
+package logger_test
+
+import (
+	"bytes"
+	"github.com/sebuckler/teel/internal/fs"
+	"github.com/sebuckler/teel/internal/logger"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSink(t *testing.T) {
+	for name, test := range getSinkTestCases() {
+		test(t, name)
+	}
+}
+
+func getSinkTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should filter entries by sink level":       shouldFilterEntriesBySinkLevel,
+		"should skip console sinks without console": shouldSkipConsoleSinksWithoutConsole,
+		"should write file sink on flush":           shouldWriteFileSinkOnFlush,
+		"should close file sink on set sinks":       shouldCloseFileSinkOnSetSinks,
+	}
+}
+
+type messageFormatter struct{}
+
+func (m *messageFormatter) Format(e *logger.Entry) ([]byte, error) {
+	message := e.Level.String() + ": " + e.Message
+
+	for _, field := range e.Fields {
+		if value, ok := field.Value.(string); ok {
+			message += " " + field.Key + "=" + value
+		}
+	}
+
+	return []byte(message + "\n"), nil
+}
+
+func shouldFilterEntriesBySinkLevel(t *testing.T, n string) {
+	console := &bytes.Buffer{}
+	file := &bytes.Buffer{}
+	l := logger.New(
+		logger.NewConsoleSink(console, logger.LevelWarn, &messageFormatter{}),
+		logger.NewSink(file, logger.LevelDebug, &messageFormatter{}),
+	)
+
+	l.Debug("resolving config")
+	l.Warnf("missing %s", "theme")
+
+	if console.String() != "warn: missing theme\n" {
+		t.Fail()
+		t.Log(n + ": console sink wrote '" + console.String() + "'")
+	}
+
+	if file.String() != "debug: resolving config\nwarn: missing theme\n" {
+		t.Fail()
+		t.Log(n + ": file sink wrote '" + file.String() + "'")
+	}
+}
+
+func shouldSkipConsoleSinksWithoutConsole(t *testing.T, n string) {
+	console := &bytes.Buffer{}
+	file := &bytes.Buffer{}
+	l := logger.New(
+		logger.NewConsoleSink(console, logger.LevelDebug, &messageFormatter{}),
+		logger.NewSink(file, logger.LevelDebug, &messageFormatter{}),
+	)
+
+	l.WithoutConsole().With(logger.F(logger.CommandKey, "new")).Error("scaffold failed")
+
+	if console.Len() != 0 {
+		t.Fail()
+		t.Log(n + ": console sink wrote '" + console.String() + "'")
+	}
+
+	if file.String() != "error: scaffold failed command=new\n" {
+		t.Fail()
+		t.Log(n + ": file sink wrote '" + file.String() + "'")
+	}
+}
+
+func shouldWriteFileSinkOnFlush(t *testing.T, n string) {
+	dir, dirErr := ioutil.TempDir("", "teel-sink")
+
+	if dirErr != nil {
+		t.Fatal(dirErr)
+	}
+
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "logs", "teel.log")
+	sink, sinkErr := logger.OpenFileSink(p, nil, &fs.BufferConfig{FlushInterval: time.Hour, FlushSize: 4096}, logger.LevelInfo, &messageFormatter{})
+
+	if sinkErr != nil {
+		t.Fatal(sinkErr)
+	}
+
+	l := logger.New(sink)
+	defer l.Close()
+
+	l.Info("site built")
+
+	if content, _ := ioutil.ReadFile(p); len(content) != 0 {
+		t.Fail()
+		t.Log(n + ": file sink wrote '" + string(content) + "' before flush")
+	}
+
+	if flushErr := l.Flush(); flushErr != nil {
+		t.Fatal(flushErr)
+	}
+
+	if content, _ := ioutil.ReadFile(p); string(content) != "info: site built\n" {
+		t.Fail()
+		t.Log(n + ": file sink wrote '" + string(content) + "' after flush")
+	}
+}
+
+func shouldCloseFileSinkOnSetSinks(t *testing.T, n string) {
+	dir, dirErr := ioutil.TempDir("", "teel-sink")
+
+	if dirErr != nil {
+		t.Fatal(dirErr)
+	}
+
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "teel.log")
+	sink, sinkErr := logger.OpenFileSink(p, nil, nil, logger.LevelInfo, &messageFormatter{})
+
+	if sinkErr != nil {
+		t.Fatal(sinkErr)
+	}
+
+	replacement := &bytes.Buffer{}
+	l := logger.New(sink)
+
+	l.Info("before")
+
+	if setErr := l.SetSinks(logger.NewSink(replacement, logger.LevelInfo, &messageFormatter{})); setErr != nil {
+		t.Fatal(setErr)
+	}
+
+	l.Info("after")
+
+	if content, _ := ioutil.ReadFile(p); string(content) != "info: before\n" {
+		t.Fail()
+		t.Log(n + ": replaced file sink contains '" + string(content) + "'")
+	}
+
+	if replacement.String() != "info: after\n" {
+		t.Fail()
+		t.Log(n + ": replacement sink wrote '" + replacement.String() + "'")
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"github.com/sebuckler/teel/internal/fs"
+	"github.com/sebuckler/teel/internal/services"
+	"github.com/sebuckler/teel/internal/site"
+	"github.com/sebuckler/teel/pkg/cli"
+	"time"
+)
+
+const DefaultLockTimeout = 10 * time.Second
+
+func SiteLock(d *string, t time.Duration) cli.Middleware {
+	return func(next cli.Handler) cli.Handler {
+		return func(ctx context.Context, o []string) error {
+			lockPath, pathErr := site.LockFile(*d)
+
+			if pathErr != nil {
+				return pathErr
+			}
+
+			lock, lockErr := fs.AcquireLock(ctx, lockPath, cli.CommandPath(ctx), t)
+
+			if lockErr != nil {
+				return lockErr
+			}
+
+			if lock.Stale != nil {
+				services.Logger(ctx).Warnf("recovered site lock left by pid %d (%s)\n", lock.Stale.PID, lock.Stale.Command)
+			}
+
+			runErr := next(ctx, o)
+
+			if unlockErr := lock.Unlock(); unlockErr != nil && runErr == nil {
+				return unlockErr
+			}
+
+			return runErr
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/sebuckler/teel/internal/fs"
+	"github.com/sebuckler/teel/internal/logger"
+	"github.com/sebuckler/teel/internal/middleware"
+	"github.com/sebuckler/teel/internal/site"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSiteLock(t *testing.T) {
+	for name, test := range getSiteLockTestCases() {
+		test(t, name)
+	}
+}
+
+func getSiteLockTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should hold site lock while running":       shouldHoldSiteLockWhileRunning,
+		"should not run when site is locked":        shouldNotRunWhenSiteIsLocked,
+		"should release site lock after error":      shouldReleaseSiteLockAfterError,
+		"should warn about lock left by crash":      shouldWarnAboutLockLeftByCrash,
+		"should lock directory given after parsing": shouldLockDirectoryGivenAfterParsing,
+	}
+}
+
+func makeSiteDir(t *testing.T) string {
+	dir, dirErr := ioutil.TempDir("", "teel-middleware")
+
+	if dirErr != nil {
+		t.Fatal(dirErr)
+	}
+
+	return dir
+}
+
+func runLocked(d *string, h func(ctx context.Context) error) error {
+	return middleware.SiteLock(d, 0)(func(ctx context.Context, o []string) error {
+		return h(ctx)
+	})(context.Background(), nil)
+}
+
+func isSiteLocked(t *testing.T, d string) bool {
+	lockPath, pathErr := site.LockFile(d)
+
+	if pathErr != nil {
+		t.Fatal(pathErr)
+	}
+
+	lock, lockErr := fs.AcquireLock(context.Background(), lockPath, "teel test", 0)
+
+	if lockErr != nil {
+		return true
+	}
+
+	_ = lock.Unlock()
+
+	return false
+}
+
+func shouldHoldSiteLockWhileRunning(t *testing.T, n string) {
+	dir := makeSiteDir(t)
+	defer os.RemoveAll(dir)
+
+	locked := false
+	runErr := runLocked(&dir, func(context.Context) error {
+		locked = isSiteLocked(t, dir)
+
+		return nil
+	})
+
+	if _, statErr := os.Stat(filepath.Join(dir, site.StateDirName)); runErr != nil || !locked || !os.IsNotExist(statErr) {
+		t.Fail()
+		t.Logf("%s: got %v, locked %t, state dir %v", n, runErr, locked, statErr)
+	}
+}
+
+func shouldNotRunWhenSiteIsLocked(t *testing.T, n string) {
+	dir := makeSiteDir(t)
+	defer os.RemoveAll(dir)
+
+	lockPath, _ := site.LockFile(dir)
+	lock, lockErr := fs.AcquireLock(context.Background(), lockPath, "teel serve", 0)
+
+	if lockErr != nil {
+		t.Fatal(lockErr)
+	}
+
+	defer lock.Unlock()
+
+	ran := false
+	runErr := runLocked(&dir, func(context.Context) error {
+		ran = true
+
+		return nil
+	})
+	var heldErr *fs.LockHeldError
+
+	if ran || !errors.As(runErr, &heldErr) || heldErr.Holder == nil || heldErr.Holder.Command != "teel serve" {
+		t.Fail()
+		t.Logf("%s: got %v and ran %t", n, runErr, ran)
+	}
+}
+
+func shouldReleaseSiteLockAfterError(t *testing.T, n string) {
+	dir := makeSiteDir(t)
+	defer os.RemoveAll(dir)
+
+	expected := errors.New("scaffold failed")
+	runErr := runLocked(&dir, func(context.Context) error { return expected })
+
+	if runErr != expected || isSiteLocked(t, dir) {
+		t.Fail()
+		t.Logf("%s: got %v or lock not released", n, runErr)
+	}
+}
+
+func shouldWarnAboutLockLeftByCrash(t *testing.T, n string) {
+	dir := makeSiteDir(t)
+	defer os.RemoveAll(dir)
+
+	lockPath, _ := site.LockFile(dir)
+	data, _ := json.Marshal(&fs.LockInfo{Command: "teel serve", Host: "laptop", PID: 42})
+
+	if mkdirErr := os.MkdirAll(filepath.Dir(lockPath), 0755); mkdirErr != nil {
+		t.Fatal(mkdirErr)
+	}
+
+	if writeErr := ioutil.WriteFile(lockPath, data, 0600); writeErr != nil {
+		t.Fatal(writeErr)
+	}
+
+	buffer := &bytes.Buffer{}
+	l := logger.New(logger.NewSink(buffer, logger.LevelInfo, logger.NewTextFormatter()))
+	runErr := middleware.SiteLock(&dir, 0)(func(context.Context, []string) error {
+		return nil
+	})(logger.WithContext(context.Background(), l), nil)
+
+	if runErr != nil || !strings.Contains(buffer.String(), "recovered site lock left by pid 42 (teel serve)") {
+		t.Fail()
+		t.Log(n + ": logged '" + buffer.String() + "'")
+	}
+}
+
+func shouldLockDirectoryGivenAfterParsing(t *testing.T, n string) {
+	dir := makeSiteDir(t)
+	defer os.RemoveAll(dir)
+
+	target := ""
+	lockMiddleware := middleware.SiteLock(&target, 0)
+	target = filepath.Join(dir, "blog")
+	locked := false
+	runErr := lockMiddleware(func(context.Context, []string) error {
+		locked = isSiteLocked(t, target)
+
+		return nil
+	})(context.Background(), nil)
+
+	if runErr != nil || !locked {
+		t.Fail()
+		t.Logf("%s: got %v and locked %t", n, runErr, locked)
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"github.com/sebuckler/teel/internal/logger"
+	"github.com/sebuckler/teel/pkg/cli"
+	"time"
+)
+
+func Logging(l logger.Logger) cli.Middleware {
+	return func(next cli.Handler) cli.Handler {
+		return func(ctx context.Context, o []string) error {
+			l.Redact(cli.SecretValues(ctx)...)
+			command := cli.CommandPath(ctx)
+			commandLogger := l.With(logger.F(logger.CommandKey, command))
+			start := time.Now()
+			commandLogger.Infof("started %s\n", command)
+
+			if err := next(logger.WithContext(ctx, commandLogger), o); err != nil {
+				commandLogger.WithoutConsole().Errorf("failed %s after %s: %v\n", command, time.Since(start), err)
+
+				return err
+			}
+
+			commandLogger.Infof("finished %s in %s\n", command, time.Since(start))
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/sebuckler/teel/internal/logger"
+	"github.com/sebuckler/teel/internal/middleware"
+	"github.com/sebuckler/teel/internal/services"
+	"github.com/sebuckler/teel/pkg/cli"
+	"strings"
+	"testing"
+)
+
+func TestLogging(t *testing.T) {
+	for name, test := range getLoggingTestCases() {
+		test(t, name)
+	}
+}
+
+func getLoggingTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should log start and finish of command":   shouldLogStartAndFinishOfCommand,
+		"should log failure without console":       shouldLogFailureWithoutConsole,
+		"should pass command logger to handler":    shouldPassCommandLoggerToHandler,
+		"should redact secret values from handler": shouldRedactSecretValuesFromHandler,
+	}
+}
+
+func newLoggingTestLogger() (logger.Logger, *bytes.Buffer, *bytes.Buffer) {
+	console := &bytes.Buffer{}
+	file := &bytes.Buffer{}
+	l := logger.New(
+		logger.NewConsoleSink(console, logger.LevelInfo, logger.NewTextFormatter()),
+		logger.NewSink(file, logger.LevelInfo, logger.NewTextFormatter()),
+	)
+
+	return l, console, file
+}
+
+func runLoggedCommand(l logger.Logger, a []string, r cli.RunErrorFunc) error {
+	token := ""
+	cmd := cli.NewCommand("teel", context.Background())
+	build := cli.NewCommand("build", context.Background())
+	build.AddStringArg(&token, &cli.ArgDefinition{Name: "token", Required: true, Secret: true})
+	build.AddRunErrorFunc(r)
+	cmd.AddSubcommand(build)
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.SetArgs(a)
+	parser.SetEnv([]string{})
+	runner := cli.NewRunner(parser, "v1", &strings.Builder{})
+	runner.SetErrorWriter(&strings.Builder{})
+	runner.AddMiddleware(middleware.Logging(l))
+
+	return runner.Run()
+}
+
+func shouldLogStartAndFinishOfCommand(t *testing.T, n string) {
+	l, _, file := newLoggingTestLogger()
+	runErr := runLoggedCommand(l, []string{"build"}, func(context.Context, []string) error { return nil })
+	logged := file.String()
+
+	if runErr != nil || !strings.Contains(logged, "started teel build command=\"teel build\"") ||
+		!strings.Contains(logged, "finished teel build in ") {
+		t.Fail()
+		t.Log(n + ": logged '" + logged + "'")
+	}
+}
+
+func shouldLogFailureWithoutConsole(t *testing.T, n string) {
+	expected := errors.New("build failed")
+	l, console, file := newLoggingTestLogger()
+	runErr := runLoggedCommand(l, []string{"build"}, func(context.Context, []string) error { return expected })
+
+	if !errors.Is(runErr, expected) || !strings.Contains(file.String(), "failed teel build after ") ||
+		!strings.Contains(file.String(), ": build failed") || strings.Contains(console.String(), "failed") {
+		t.Fail()
+		t.Log(n + ": logged '" + file.String() + "' to file and '" + console.String() + "' to console")
+	}
+}
+
+func shouldPassCommandLoggerToHandler(t *testing.T, n string) {
+	l, _, file := newLoggingTestLogger()
+	runErr := runLoggedCommand(l, []string{"build"}, func(ctx context.Context, o []string) error {
+		services.Logger(ctx).Info("rendering pages")
+
+		return nil
+	})
+
+	if runErr != nil || !strings.Contains(file.String(), "rendering pages command=\"teel build\"") {
+		t.Fail()
+		t.Log(n + ": logged '" + file.String() + "'")
+	}
+}
+
+func shouldRedactSecretValuesFromHandler(t *testing.T, n string) {
+	l, _, file := newLoggingTestLogger()
+	runErr := runLoggedCommand(l, []string{"build", "--token", "correct-horse"}, func(ctx context.Context, o []string) error {
+		services.Logger(ctx).Infof("deploying with %s\n", "correct-horse")
+
+		return nil
+	})
+
+	if runErr != nil || strings.Contains(file.String(), "correct-horse") ||
+		!strings.Contains(file.String(), "deploying with "+logger.RedactedText) {
+		t.Fail()
+		t.Log(n + ": logged '" + file.String() + "'")
+	}
+}
@@ -1,25 +1,33 @@
 package directives
 
 import (
+	"github.com/sebuckler/teel/internal/fs"
 	"os"
 	"path"
 )
 
 type ConfigDirective struct {
+	fileSystem fs.FileSystem
 }
 
-func NewConfig() Directive {
-	return &ConfigDirective{}
+func NewConfig(f fs.FileSystem) Directive {
+	return &ConfigDirective{
+		fileSystem: f,
+	}
 }
 
 func (c *ConfigDirective) Execute(d string, n string) error {
-	mkdirErr := os.MkdirAll(path.Join(d, "server"), 0755)
+	mkdirErr := c.fileSystem.MkdirAll(path.Join(d, "server"), 0755)
 
 	if mkdirErr != nil {
 		return mkdirErr
 	}
 
-	_, fileErr := os.OpenFile(path.Join(d, "config.json"), os.O_RDWR|os.O_APPEND|os.O_CREATE, 0755)
+	configPath := path.Join(d, "config.json")
+
+	if _, statErr := c.fileSystem.Stat(configPath); !os.IsNotExist(statErr) {
+		return statErr
+	}
 
-	return fileErr
+	return c.fileSystem.WriteFile(configPath, []byte{}, 0755)
 }
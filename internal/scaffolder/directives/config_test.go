@@ -0,0 +1,58 @@
+package directives_test
+
+import (
+	"github.com/sebuckler/teel/internal/fs"
+	"github.com/sebuckler/teel/internal/scaffolder/directives"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigDirective_Execute(t *testing.T) {
+	for name, test := range getConfigTestCases() {
+		test(t, name)
+	}
+}
+
+func getConfigTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should create server directory and config": shouldCreateServerDirectoryAndConfig,
+		"should keep existing config":               shouldKeepExistingConfig,
+		"should error when site path is a file":     shouldErrorWhenSitePathIsAFile,
+	}
+}
+
+func shouldCreateServerDirectoryAndConfig(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	execErr := directives.NewConfig(memFs).Execute("blog", "my blog")
+	server, serverErr := memFs.Stat(filepath.Join("blog", "server"))
+	config, configErr := memFs.Stat(filepath.Join("blog", "config.json"))
+
+	if execErr != nil || serverErr != nil || configErr != nil || !server.IsDir() || config.IsDir() {
+		t.Fail()
+		t.Log(n + ": server directory and config not created")
+	}
+}
+
+func shouldKeepExistingConfig(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	configPath := filepath.Join("blog", "config.json")
+	_ = memFs.MkdirAll("blog", 0755)
+	_ = memFs.WriteFile(configPath, []byte(`{"name":"old"}`), 0644)
+	execErr := directives.NewConfig(memFs).Execute("blog", "my blog")
+	data, _ := memFs.ReadFile(configPath)
+
+	if execErr != nil || string(data) != `{"name":"old"}` {
+		t.Fail()
+		t.Log(n + ": existing config overwritten")
+	}
+}
+
+func shouldErrorWhenSitePathIsAFile(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	_ = memFs.WriteFile("blog", []byte{}, 0644)
+
+	if execErr := directives.NewConfig(memFs).Execute("blog", "my blog"); execErr == nil {
+		t.Fail()
+		t.Log(n + ": scaffolded config under a file")
+	}
+}
@@ -2,8 +2,11 @@ package scaffolder
 
 import (
 	"errors"
+	"github.com/sebuckler/teel/internal/fs"
 	"github.com/sebuckler/teel/internal/scaffolder/directives"
+	"github.com/sebuckler/teel/internal/site"
 	"os"
+	"path/filepath"
 )
 
 type Scaffolder interface {
@@ -12,16 +15,18 @@ type Scaffolder interface {
 
 type scaffolder struct {
 	directives []directives.Directive
+	fileSystem fs.FileSystem
 }
 
-func New(d ...directives.Directive) Scaffolder {
+func New(f fs.FileSystem, d ...directives.Directive) Scaffolder {
 	return &scaffolder{
 		directives: d,
+		fileSystem: f,
 	}
 }
 
 func (s *scaffolder) Scaffold(d string, n string) error {
-	if _, statErr := os.Stat(d); !os.IsNotExist(statErr) {
+	if _, statErr := s.fileSystem.Stat(d); !os.IsNotExist(statErr) && !s.isEmptySite(d) {
 		return errors.New("directory already exists at " + d)
 	}
 
@@ -39,3 +44,25 @@ func (s *scaffolder) Scaffold(d string, n string) error {
 
 	return nil
 }
+
+func (s *scaffolder) isEmptySite(d string) bool {
+	root := filepath.Clean(d)
+	stateDir := filepath.Join(root, site.StateDirName)
+	empty := true
+	walkErr := s.fileSystem.Walk(root, func(p string, i os.FileInfo, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case p == root && i.IsDir():
+			return nil
+		case p == stateDir && i.IsDir():
+			return filepath.SkipDir
+		}
+
+		empty = false
+
+		return filepath.SkipDir
+	})
+
+	return walkErr == nil && empty
+}
@@ -0,0 +1,104 @@
+package scaffolder_test
+
+import (
+	"errors"
+	"github.com/sebuckler/teel/internal/fs"
+	"github.com/sebuckler/teel/internal/scaffolder"
+	"github.com/sebuckler/teel/internal/scaffolder/directives"
+	"github.com/sebuckler/teel/internal/site"
+	"path/filepath"
+	"testing"
+)
+
+func TestScaffolder_Scaffold(t *testing.T) {
+	for name, test := range getScaffoldTestCases() {
+		test(t, name)
+	}
+}
+
+func getScaffoldTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should scaffold site in new directory":  shouldScaffoldSiteInNewDirectory,
+		"should error when directory exists":     shouldErrorWhenDirectoryExists,
+		"should scaffold beside site state dir":  shouldScaffoldBesideSiteStateDir,
+		"should error when name is missing":      shouldErrorWhenNameIsMissing,
+		"should stop at first failing directive": shouldStopAtFirstFailingDirective,
+	}
+}
+
+type directiveFunc func(d string, n string) error
+
+func (f directiveFunc) Execute(d string, n string) error {
+	return f(d, n)
+}
+
+func shouldScaffoldSiteInNewDirectory(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	scaffoldErr := scaffolder.New(memFs, directives.NewConfig(memFs)).Scaffold("blog", "my blog")
+	_, configErr := memFs.Stat(filepath.Join("blog", "config.json"))
+	server, serverErr := memFs.Stat(filepath.Join("blog", "server"))
+
+	if scaffoldErr != nil || configErr != nil || serverErr != nil || !server.IsDir() {
+		t.Fail()
+		t.Log(n + ": site not scaffolded")
+	}
+}
+
+func shouldErrorWhenDirectoryExists(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	_ = memFs.MkdirAll(filepath.Join("blog", site.StateDirName), 0755)
+	_ = memFs.WriteFile(filepath.Join("blog", "index.html"), []byte("<html></html>"), 0644)
+	executed := false
+	siteScaffolder := scaffolder.New(memFs, directiveFunc(func(string, string) error {
+		executed = true
+
+		return nil
+	}))
+
+	if scaffoldErr := siteScaffolder.Scaffold("blog", "my blog"); scaffoldErr == nil || executed {
+		t.Fail()
+		t.Log(n + ": scaffolded into existing directory")
+	}
+}
+
+func shouldScaffoldBesideSiteStateDir(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	_ = memFs.MkdirAll(filepath.Join("blog", site.StateDirName, "logs"), 0755)
+	scaffoldErr := scaffolder.New(memFs, directives.NewConfig(memFs)).Scaffold("blog", "my blog")
+
+	if _, configErr := memFs.Stat(filepath.Join("blog", "config.json")); scaffoldErr != nil || configErr != nil {
+		t.Fail()
+		t.Log(n + ": site not scaffolded into directory holding only the state dir")
+	}
+}
+
+func shouldErrorWhenNameIsMissing(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+
+	if scaffoldErr := scaffolder.New(memFs, directives.NewConfig(memFs)).Scaffold("blog", ""); scaffoldErr == nil {
+		t.Fail()
+		t.Log(n + ": scaffolded site without a name")
+	}
+}
+
+func shouldStopAtFirstFailingDirective(t *testing.T, n string) {
+	expected := errors.New("directive failed")
+	executed := 0
+	siteScaffolder := scaffolder.New(fs.NewMemFileSystem(),
+		directiveFunc(func(string, string) error {
+			executed++
+
+			return expected
+		}),
+		directiveFunc(func(string, string) error {
+			executed++
+
+			return nil
+		}),
+	)
+
+	if scaffoldErr := siteScaffolder.Scaffold("blog", "my blog"); scaffoldErr != expected || executed != 1 {
+		t.Fail()
+		t.Log(n + ": did not stop at failing directive")
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/sebuckler/teel/internal/fs"
+	"github.com/sebuckler/teel/internal/logger"
+	"github.com/sebuckler/teel/internal/scaffolder"
+	"github.com/sebuckler/teel/internal/site"
+	"github.com/sebuckler/teel/pkg/cli"
+	"sync"
+)
+
+type Services struct {
+	FileSystem fs.FileSystem
+	Logger     logger.Logger
+	Scaffolder scaffolder.Scaffolder
+	Paths      *site.Paths
+	siteConfig *site.Config
+	siteErr    error
+	siteOnce   sync.Once
+}
+
+type servicesKey struct{}
+
+func New(f fs.FileSystem, l logger.Logger, s scaffolder.Scaffolder, p *site.Paths) *Services {
+	return &Services{
+		FileSystem: f,
+		Logger:     l,
+		Paths:      p,
+		Scaffolder: s,
+	}
+}
+
+func WithServices(ctx context.Context, s *Services) context.Context {
+	return context.WithValue(ctx, servicesKey{}, s)
+}
+
+func FromContext(ctx context.Context) (*Services, bool) {
+	s, ok := ctx.Value(servicesKey{}).(*Services)
+
+	return s, ok && s != nil
+}
+
+func Middleware(s *Services) cli.Middleware {
+	return func(next cli.Handler) cli.Handler {
+		return func(ctx context.Context, o []string) error {
+			return next(WithServices(ctx, s), o)
+		}
+	}
+}
+
+func FileSystem(ctx context.Context) fs.FileSystem {
+	if s, ok := FromContext(ctx); ok && s.FileSystem != nil {
+		return s.FileSystem
+	}
+
+	return fs.NewOSFileSystem()
+}
+
+func Logger(ctx context.Context) logger.Logger {
+	if l, ok := logger.FromContext(ctx); ok {
+		return l
+	}
+
+	if s, ok := FromContext(ctx); ok && s.Logger != nil {
+		return s.Logger
+	}
+
+	return logger.New()
+}
+
+func Scaffolder(ctx context.Context) scaffolder.Scaffolder {
+	if s, ok := FromContext(ctx); ok {
+		return s.Scaffolder
+	}
+
+	return nil
+}
+
+func Paths(ctx context.Context) *site.Paths {
+	if s, ok := FromContext(ctx); ok {
+		return s.Paths
+	}
+
+	return nil
+}
+
+func SiteConfig(ctx context.Context) (*site.Config, error) {
+	s, ok := FromContext(ctx)
+
+	if !ok {
+		return nil, errors.New("services not configured for command")
+	}
+
+	s.siteOnce.Do(func() {
+		if s.Paths == nil || s.Paths.Root == "" {
+			s.siteErr = errors.New("not inside a teel site: no " + site.ConfigFileName + " found")
+
+			return
+		}
+
+		s.siteConfig, s.siteErr = site.LoadConfig(s.FileSystem, s.Paths.Root)
+	})
+
+	return s.siteConfig, s.siteErr
+}
@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/sebuckler/teel/internal/fs"
+	"github.com/sebuckler/teel/internal/logger"
+	"github.com/sebuckler/teel/internal/scaffolder"
+	"github.com/sebuckler/teel/internal/services"
+	"github.com/sebuckler/teel/internal/site"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServices(t *testing.T) {
+	for name, test := range getServicesTestCases() {
+		test(t, name)
+	}
+}
+
+func getServicesTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should return configured services":        shouldReturnConfiguredServices,
+		"should fall back without services":        shouldFallBackWithoutServices,
+		"should prefer command logger":             shouldPreferCommandLogger,
+		"should add services in middleware":        shouldAddServicesInMiddleware,
+		"should load site config once":             shouldLoadSiteConfigOnce,
+		"should error on site config outside site": shouldErrorOnSiteConfigOutsideSite,
+	}
+}
+
+func newTestServices(f fs.FileSystem, l logger.Logger, p *site.Paths) *services.Services {
+	return services.New(f, l, scaffolder.New(f), p)
+}
+
+func shouldReturnConfiguredServices(t *testing.T, n string) {
+	memFs := fs.NewMemFileSystem()
+	l := logger.New()
+	paths := &site.Paths{State: filepath.FromSlash("/state")}
+	s := newTestServices(memFs, l, paths)
+	ctx := services.WithServices(context.Background(), s)
+
+	if services.FileSystem(ctx) != memFs || services.Logger(ctx) != l || services.Scaffolder(ctx) != s.Scaffolder ||
+		services.Paths(ctx) != paths {
+		t.Fail()
+		t.Log(n + ": configured services not returned")
+	}
+}
+
+func shouldFallBackWithoutServices(t *testing.T, n string) {
+	ctx := context.Background()
+
+	if _, ok := services.FromContext(ctx); ok || services.FileSystem(ctx) == nil || services.Logger(ctx) == nil ||
+		services.Scaffolder(ctx) != nil || services.Paths(ctx) != nil {
+		t.Fail()
+		t.Log(n + ": unexpected fallbacks without services")
+	}
+
+	if _, configErr := services.SiteConfig(ctx); configErr == nil {
+		t.Fail()
+		t.Log(n + ": loaded site config without services")
+	}
+}
+
+func shouldPreferCommandLogger(t *testing.T, n string) {
+	buffer := &bytes.Buffer{}
+	commandLogger := logger.New(logger.NewSink(buffer, logger.LevelInfo, logger.NewTextFormatter()))
+	ctx := services.WithServices(context.Background(), newTestServices(fs.NewMemFileSystem(), logger.New(), nil))
+	services.Logger(logger.WithContext(ctx, commandLogger)).Info("rendering pages")
+
+	if !strings.Contains(buffer.String(), "rendering pages") {
+		t.Fail()
+		t.Log(n + ": command logger not used")
+	}
+}
+
+func shouldAddServicesInMiddleware(t *testing.T, n string) {
+	s := newTestServices(fs.NewMemFileSystem(), logger.New(), nil)
+	var found *services.Services
+	runErr := services.Middleware(s)(func(ctx context.Context, o []string) error {
+		found, _ = services.FromContext(ctx)
+
+		return nil
+	})(context.Background(), nil)
+
+	if runErr != nil || found != s {
+		t.Fail()
+		t.Log(n + ": services not added to command context")
+	}
+}
+
+func shouldLoadSiteConfigOnce(t *testing.T, n string) {
+	root := filepath.FromSlash("/srv/blog")
+	configPath := filepath.Join(root, site.ConfigFileName)
+	memFs := fs.NewMemFileSystem()
+	_ = memFs.MkdirAll(root, 0755)
+	_ = memFs.WriteFile(configPath, []byte(`{"name": "blog"}`), 0644)
+	ctx := services.WithServices(context.Background(), newTestServices(memFs, logger.New(), &site.Paths{Root: root}))
+	first, firstErr := services.SiteConfig(ctx)
+	_ = memFs.Remove(configPath)
+	second, secondErr := services.SiteConfig(ctx)
+
+	if firstErr != nil || secondErr != nil || first != second || first.Name != "blog" {
+		t.Fail()
+		t.Logf("%s: got %v and %v", n, firstErr, secondErr)
+	}
+}
+
+func shouldErrorOnSiteConfigOutsideSite(t *testing.T, n string) {
+	paths := &site.Paths{State: filepath.FromSlash("/state")}
+	ctx := services.WithServices(context.Background(), newTestServices(fs.NewMemFileSystem(), logger.New(), paths))
+
+	if _, configErr := services.SiteConfig(ctx); configErr == nil || !strings.Contains(configErr.Error(), "not inside a teel site") {
+		t.Fail()
+		t.Logf("%s: got %v", n, configErr)
+	}
+}
@@ -0,0 +1,34 @@
+package site
+
+import (
+	"encoding/json"
+	"github.com/sebuckler/teel/internal/fs"
+	"path/filepath"
+	"strings"
+)
+
+const ConfigFileName = "config.json"
+
+type Config struct {
+	Name string `json:"name"`
+}
+
+func LoadConfig(f fs.FileSystem, d string) (*Config, error) {
+	data, readErr := f.ReadFile(filepath.Join(d, ConfigFileName))
+
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	config := &Config{}
+
+	if strings.TrimSpace(string(data)) == "" {
+		return config, nil
+	}
+
+	if jsonErr := json.Unmarshal(data, config); jsonErr != nil {
+		return nil, jsonErr
+	}
+
+	return config, nil
+}
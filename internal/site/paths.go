@@ -0,0 +1,110 @@
+package site
+
+import (
+	"errors"
+	"github.com/sebuckler/teel/internal/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	StateDirEnv  = "TEEL_STATE_DIR"
+	StateDirName = ".teel"
+	xdgStateEnv  = "XDG_STATE_HOME"
+)
+
+type Paths struct {
+	Root  string
+	State string
+}
+
+func FindRoot(f fs.FileSystem, d string) (string, bool) {
+	dir, absErr := filepath.Abs(d)
+
+	if absErr != nil {
+		return "", false
+	}
+
+	for {
+		if info, statErr := f.Stat(filepath.Join(dir, ConfigFileName)); statErr == nil && !info.IsDir() {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+
+		if parent == dir {
+			return "", false
+		}
+
+		dir = parent
+	}
+}
+
+func LockFile(d string) (string, error) {
+	dir, absErr := filepath.Abs(d)
+
+	if absErr != nil {
+		return "", absErr
+	}
+
+	return filepath.Join(dir, StateDirName, "site.lock"), nil
+}
+
+func ResolvePaths(f fs.FileSystem, d string, s string) (*Paths, error) {
+	root, _ := FindRoot(f, d)
+	paths := &Paths{Root: root}
+
+	switch {
+	case s != "":
+		paths.State = s
+	case os.Getenv(StateDirEnv) != "":
+		paths.State = os.Getenv(StateDirEnv)
+	case root != "":
+		paths.State = filepath.Join(root, StateDirName)
+	default:
+		stateHome, stateErr := getStateHome()
+
+		if stateErr != nil {
+			return nil, stateErr
+		}
+
+		paths.State = filepath.Join(stateHome, "teel")
+	}
+
+	return paths, nil
+}
+
+func (p *Paths) CrashDir() string {
+	return filepath.Join(p.State, "crash")
+}
+
+func (p *Paths) LogDir() string {
+	return filepath.Join(p.State, "logs")
+}
+
+func (p *Paths) LogFile() string {
+	return filepath.Join(p.LogDir(), "teel.log")
+}
+
+func (p *Paths) PluginDir() string {
+	return filepath.Join(p.State, "plugins")
+}
+
+func getStateHome() (string, error) {
+	if stateHome := os.Getenv(xdgStateEnv); stateHome != "" {
+		return stateHome, nil
+	}
+
+	if runtime.GOOS == "windows" {
+		return os.UserCacheDir()
+	}
+
+	home, homeErr := os.UserHomeDir()
+
+	if homeErr != nil {
+		return "", errors.New("unable to resolve state directory: " + homeErr.Error())
+	}
+
+	return filepath.Join(home, ".local", "state"), nil
+}
@@ -0,0 +1,156 @@
+package site_test
+
+import (
+	"github.com/sebuckler/teel/internal/fs"
+	"github.com/sebuckler/teel/internal/site"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindRoot(t *testing.T) {
+	for name, test := range getFindRootTestCases() {
+		test(t, name)
+	}
+}
+
+func getFindRootTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should find root from nested directory": shouldFindRootFromNestedDirectory,
+		"should not find root outside a site":    shouldNotFindRootOutsideASite,
+	}
+}
+
+func newSiteFileSystem(t *testing.T, r string) fs.FileSystem {
+	memFs := fs.NewMemFileSystem()
+
+	if mkdirErr := memFs.MkdirAll(filepath.Join(r, "server", "pages"), 0755); mkdirErr != nil {
+		t.Fatal(mkdirErr)
+	}
+
+	if writeErr := memFs.WriteFile(filepath.Join(r, site.ConfigFileName), []byte("{}"), 0644); writeErr != nil {
+		t.Fatal(writeErr)
+	}
+
+	return memFs
+}
+
+func shouldFindRootFromNestedDirectory(t *testing.T, n string) {
+	root := filepath.FromSlash("/srv/blog")
+	memFs := newSiteFileSystem(t, root)
+	found, ok := site.FindRoot(memFs, filepath.Join(root, "server", "pages"))
+
+	if !ok || found != root {
+		t.Fail()
+		t.Log(n + ": found root '" + found + "'")
+	}
+}
+
+func shouldNotFindRootOutsideASite(t *testing.T, n string) {
+	memFs := newSiteFileSystem(t, filepath.FromSlash("/srv/blog"))
+
+	if found, ok := site.FindRoot(memFs, filepath.FromSlash("/srv/other")); ok {
+		t.Fail()
+		t.Log(n + ": found root '" + found + "' outside a site")
+	}
+}
+
+func TestResolvePaths(t *testing.T) {
+	for name, test := range getResolvePathsTestCases() {
+		test(t, name)
+	}
+}
+
+func getResolvePathsTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should prefer explicit state dir":       shouldPreferExplicitStateDir,
+		"should use state dir env over site":     shouldUseStateDirEnvOverSite,
+		"should use site state dir inside site":  shouldUseSiteStateDirInsideSite,
+		"should use xdg state home outside site": shouldUseXDGStateHomeOutsideSite,
+	}
+}
+
+func setTestEnv(t *testing.T, e map[string]string) func() {
+	previous := map[string]*string{}
+
+	for key, value := range e {
+		if current, ok := os.LookupEnv(key); ok {
+			previous[key] = &current
+		} else {
+			previous[key] = nil
+		}
+
+		if setErr := os.Setenv(key, value); setErr != nil {
+			t.Fatal(setErr)
+		}
+	}
+
+	return func() {
+		for key, value := range previous {
+			if value == nil {
+				_ = os.Unsetenv(key)
+			} else {
+				_ = os.Setenv(key, *value)
+			}
+		}
+	}
+}
+
+func shouldPreferExplicitStateDir(t *testing.T, n string) {
+	defer setTestEnv(t, map[string]string{site.StateDirEnv: filepath.FromSlash("/env/state")})()
+
+	root := filepath.FromSlash("/srv/blog")
+	paths, pathsErr := site.ResolvePaths(newSiteFileSystem(t, root), root, filepath.FromSlash("/flag/state"))
+
+	if pathsErr != nil || paths.Root != root || paths.State != filepath.FromSlash("/flag/state") {
+		t.Fail()
+		t.Log(n + ": explicit state dir not used")
+	}
+}
+
+func shouldUseStateDirEnvOverSite(t *testing.T, n string) {
+	defer setTestEnv(t, map[string]string{site.StateDirEnv: filepath.FromSlash("/env/state")})()
+
+	root := filepath.FromSlash("/srv/blog")
+	paths, pathsErr := site.ResolvePaths(newSiteFileSystem(t, root), root, "")
+
+	if pathsErr != nil || paths.State != filepath.FromSlash("/env/state") {
+		t.Fail()
+		t.Log(n + ": state dir env not used")
+	}
+}
+
+func shouldUseSiteStateDirInsideSite(t *testing.T, n string) {
+	defer setTestEnv(t, map[string]string{site.StateDirEnv: ""})()
+
+	root := filepath.FromSlash("/srv/blog")
+	paths, pathsErr := site.ResolvePaths(newSiteFileSystem(t, root), filepath.Join(root, "server"), "")
+
+	if pathsErr != nil || paths.State != filepath.Join(root, site.StateDirName) ||
+		paths.LogFile() != filepath.Join(root, site.StateDirName, "logs", "teel.log") {
+		t.Fail()
+		t.Log(n + ": site state dir not used")
+	}
+}
+
+func shouldUseXDGStateHomeOutsideSite(t *testing.T, n string) {
+	defer setTestEnv(t, map[string]string{site.StateDirEnv: "", "XDG_STATE_HOME": filepath.FromSlash("/xdg/state")})()
+
+	paths, pathsErr := site.ResolvePaths(fs.NewMemFileSystem(), filepath.FromSlash("/tmp"), "")
+
+	if pathsErr != nil || paths.Root != "" || paths.State != filepath.Join(filepath.FromSlash("/xdg/state"), "teel") {
+		t.Fail()
+		t.Log(n + ": xdg state home not used")
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	blog, blogErr := site.LockFile(filepath.FromSlash("/srv/blog"))
+	again, againErr := site.LockFile(filepath.FromSlash("/srv/blog/../blog"))
+	expected := filepath.Join(filepath.FromSlash("/srv/blog"), site.StateDirName, "site.lock")
+
+	if blogErr != nil || againErr != nil || blog != expected || again != expected {
+		t.Fail()
+		t.Log("should lock inside the target site: got '" + blog + "' and '" + again + "'")
+	}
+}
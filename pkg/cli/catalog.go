@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const DefaultLocale = "en"
+
+const (
+	MsgChoiceLabel            MessageID = "choice-label"
+	MsgCommandsHeading        MessageID = "commands-heading"
+	MsgCommandPlaceholder     MessageID = "command-placeholder"
+	MsgDirectoryNotFound      MessageID = "directory-not-found"
+	MsgErrorLabel             MessageID = "error-label"
+	MsgFailedToParseArgument  MessageID = "failed-to-parse-argument"
+	MsgFileIsDirectory        MessageID = "file-is-directory"
+	MsgFileNotAccessible      MessageID = "file-not-accessible"
+	MsgFileNotFound           MessageID = "file-not-found"
+	MsgFileNotReadable        MessageID = "file-not-readable"
+	MsgFileNotWritable        MessageID = "file-not-writable"
+	MsgHelpTopicsHeading      MessageID = "help-topics-heading"
+	MsgInvalidChoice          MessageID = "invalid-choice"
+	MsgInvalidGnuOptArg       MessageID = "invalid-gnu-option-argument"
+	MsgInvalidGnuOption       MessageID = "invalid-gnu-option"
+	MsgInvalidGnuOptionName   MessageID = "invalid-gnu-option-name"
+	MsgInvalidOperand         MessageID = "invalid-operand"
+	MsgInvalidOptArg          MessageID = "invalid-option-argument"
+	MsgInvalidOption          MessageID = "invalid-option"
+	MsgInvalidPosixOptArg     MessageID = "invalid-posix-option-argument"
+	MsgInvalidPosixOption     MessageID = "invalid-posix-option"
+	MsgInvalidPosixOptionName MessageID = "invalid-posix-option-name"
+	MsgMissingFilePath        MessageID = "missing-file-path"
+	MsgMissingOperand         MessageID = "missing-operand"
+	MsgMissingOptArg          MessageID = "missing-option-argument"
+	MsgMissingPosixOptArgs    MessageID = "missing-posix-option-arguments"
+	MsgNoCommandsParsed       MessageID = "no-commands-parsed"
+	MsgNoPromptInput          MessageID = "no-prompt-input"
+	MsgNoRootCommandParsed    MessageID = "no-root-command-parsed"
+	MsgNonRepeatableGnuOpt    MessageID = "non-repeatable-gnu-option"
+	MsgNonRepeatablePosixOpt  MessageID = "non-repeatable-posix-option"
+	MsgOperandsHeading        MessageID = "operands-heading"
+	MsgOptionalGnuOptArg      MessageID = "optional-gnu-option-argument"
+	MsgOptionsHeading         MessageID = "options-heading"
+	MsgOutputIgnored          MessageID = "output-ignored"
+	MsgPluginsHeading         MessageID = "plugins-heading"
+	MsgSuggestedCommand       MessageID = "suggested-command"
+	MsgSuggestedTopic         MessageID = "suggested-topic"
+	MsgUnexpectedCommand      MessageID = "unexpected-command"
+	MsgUnknownCommand         MessageID = "unknown-command"
+	MsgUnknownTopic           MessageID = "unknown-topic"
+	MsgUnsupportedExecMode    MessageID = "unsupported-execution-mode"
+	MsgUnsupportedSyntax      MessageID = "unsupported-syntax"
+	MsgUsageHeading           MessageID = "usage-heading"
+	MsgWarningLabel           MessageID = "warning-label"
+)
+
+var defaultMessages = Messages{
+	MsgChoiceLabel:            "Choice",
+	MsgCommandsHeading:        "Commands:",
+	MsgCommandPlaceholder:     "[command]",
+	MsgDirectoryNotFound:      "directory not found: %s",
+	MsgErrorLabel:             "Error:",
+	MsgFailedToParseArgument:  "failed to parse argument: %s",
+	MsgFileIsDirectory:        "file is a directory: %s",
+	MsgFileNotAccessible:      "file not accessible: %s",
+	MsgFileNotFound:           "file not found: %s",
+	MsgFileNotReadable:        "file not readable: %s",
+	MsgFileNotWritable:        "file not writable: %s",
+	MsgHelpTopicsHeading:      "Help topics:",
+	MsgInvalidChoice:          "invalid choice: %s",
+	MsgInvalidGnuOptArg:       "invalid GNU option argument: '%s' for option: --%s",
+	MsgInvalidGnuOption:       "invalid GNU option: %s",
+	MsgInvalidGnuOptionName:   "invalid GNU option name: --%s",
+	MsgInvalidOperand:         "invalid operand: '%s' for operand: %s",
+	MsgInvalidOptArg:          "invalid option-argument: '%s' for option: %s",
+	MsgInvalidOption:          "invalid option: %s",
+	MsgInvalidPosixOptArg:     "invalid POSIX option-argument: '%s' for option: %s",
+	MsgInvalidPosixOption:     "invalid POSIX option: %s",
+	MsgInvalidPosixOptionName: "invalid POSIX option name: -%s",
+	MsgMissingFilePath:        "missing file path",
+	MsgMissingOperand:         "missing required operand: %s",
+	MsgMissingOptArg:          "missing option-argument for required option: %s",
+	MsgMissingPosixOptArgs:    "no POSIX option-arguments provided for option: %s",
+	MsgNoCommandsParsed:       "no commands parsed",
+	MsgNoPromptInput:          "no input provided for prompt",
+	MsgNoRootCommandParsed:    "no root command parsed",
+	MsgNonRepeatableGnuOpt:    "non-repeatable GNU option: --%s",
+	MsgNonRepeatablePosixOpt:  "non-repeatable POSIX option: -%s",
+	MsgOperandsHeading:        "Operands:",
+	MsgOptionalGnuOptArg:      "optional GNU option-argument '%s' must be provided with option '--%s' separated by '='",
+	MsgOptionsHeading:         "Options:",
+	MsgOutputIgnored:          "option --output has no effect without --version",
+	MsgPluginsHeading:         "Plugins:",
+	MsgSuggestedCommand:       "unknown command: %s (did you mean %s?)",
+	MsgSuggestedTopic:         "unknown help topic or command: %s (did you mean %s?)",
+	MsgUnexpectedCommand:      "unexpected command: %s",
+	MsgUnknownCommand:         "unknown command: %s",
+	MsgUnknownTopic:           "unknown help topic or command: %s",
+	MsgUnsupportedExecMode:    "unsupported execution mode",
+	MsgUnsupportedSyntax:      "unsupported argument parsing syntax",
+	MsgUsageHeading:           "Usage:",
+	MsgWarningLabel:           "Warning:",
+}
+
+func NewCatalog(l string) Catalog {
+	messages := make(Messages, len(defaultMessages))
+
+	for id, message := range defaultMessages {
+		messages[id] = message
+	}
+
+	return &catalog{
+		locale: normalizeLocale(l),
+		messages: map[string]Messages{
+			DefaultLocale: messages,
+		},
+	}
+}
+
+func DetectLocale(e []string) string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := getenv(e, key); locale != "" {
+			return normalizeLocale(locale)
+		}
+	}
+
+	return DefaultLocale
+}
+
+func (c *catalog) AddMessages(l string, m Messages) {
+	locale := normalizeLocale(l)
+
+	if c.messages[locale] == nil {
+		c.messages[locale] = Messages{}
+	}
+
+	for id, message := range m {
+		c.messages[locale][id] = message
+	}
+}
+
+func (c *catalog) Error(id MessageID, v ...interface{}) error {
+	return errors.New(c.Message(id, v...))
+}
+
+func (c *catalog) Locale() string {
+	return c.locale
+}
+
+func (c *catalog) Message(id MessageID, v ...interface{}) string {
+	message := string(id)
+
+	for _, locale := range []string{c.locale, getBaseLocale(c.locale), DefaultLocale} {
+		if translated, ok := c.messages[locale][id]; ok {
+			message = translated
+
+			break
+		}
+	}
+
+	if len(v) == 0 {
+		return message
+	}
+
+	return fmt.Sprintf(message, v...)
+}
+
+func (c *catalog) SetLocale(l string) {
+	c.locale = normalizeLocale(l)
+}
+
+func normalizeLocale(l string) string {
+	locale := strings.SplitN(strings.SplitN(l, ".", 2)[0], "@", 2)[0]
+
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return DefaultLocale
+	}
+
+	return strings.Replace(locale, "-", "_", -1)
+}
+
+func getBaseLocale(l string) string {
+	return strings.SplitN(l, "_", 2)[0]
+}
+
+func getCatalog(c Catalog) Catalog {
+	if c == nil {
+		return NewCatalog(DetectLocale(nil))
+	}
+
+	return c
+}
+
+func translateUsage(c Catalog, u string) string {
+	if u == "" {
+		return u
+	}
+
+	return c.Message(MessageID(u))
+}
@@ -0,0 +1,108 @@
+package cli_test
+
+import (
+	"context"
+	"github.com/sebuckler/teel/pkg/cli"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCatalog_Message(t *testing.T) {
+	for name, test := range getCatalogTestCases() {
+		test(t, name)
+	}
+}
+
+func getCatalogTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should detect locale from environment":       shouldDetectLocaleFromEnvironment,
+		"should fall back to base locale and default": shouldFallBackToBaseLocaleAndDefault,
+		"should return message ID without message":    shouldReturnMessageIDWithoutMessage,
+		"should translate parser errors":              shouldTranslateParserErrors,
+		"should translate help text and usage text":   shouldTranslateHelpTextAndUsageText,
+		"should not share default messages":           shouldNotShareDefaultMessages,
+	}
+}
+
+func shouldDetectLocaleFromEnvironment(t *testing.T, n string) {
+	testCases := map[string][]string{
+		"de_DE": {"LANG=en_US.UTF-8", "LC_MESSAGES=de_DE.UTF-8"},
+		"fr":    {"LANG=fr", "LC_ALL=", "LC_MESSAGES="},
+		"es_MX": {"LANG=en_US", "LC_ALL=es_MX.UTF-8@euro", "LC_MESSAGES=de_DE"},
+		"en":    {"LANG=C"},
+	}
+
+	for expected, env := range testCases {
+		if locale := cli.DetectLocale(env); locale != expected {
+			t.Fail()
+			t.Log(n + ": detected '" + locale + "' instead of '" + expected + "'")
+		}
+	}
+}
+
+func shouldFallBackToBaseLocaleAndDefault(t *testing.T, n string) {
+	catalog := cli.NewCatalog("de_AT")
+	catalog.AddMessages("de", cli.Messages{cli.MsgOptionsHeading: "Optionen:"})
+
+	if catalog.Message(cli.MsgOptionsHeading) != "Optionen:" || catalog.Message(cli.MsgUsageHeading) != "Usage:" {
+		t.Fail()
+		t.Log(n + ": did not fall back through locales")
+	}
+}
+
+func shouldReturnMessageIDWithoutMessage(t *testing.T, n string) {
+	if message := cli.NewCatalog("en").Message("100% custom usage"); message != "100% custom usage" {
+		t.Fail()
+		t.Log(n + ": did not return untranslated message ID: " + message)
+	}
+}
+
+func shouldTranslateParserErrors(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "a"}
+	catalog := cli.NewCatalog("en")
+	catalog.AddMessages("de", cli.Messages{cli.MsgInvalidGnuOption: "ungültige GNU-Option: %s"})
+	catalog.SetLocale("de")
+	parser := cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background()))
+	parser.SetCatalog(catalog)
+	_, err := parser.Parse()
+
+	if err == nil || err.Error() != "ungültige GNU-Option: a" {
+		t.Fail()
+		t.Log(n + ": parser error not translated")
+	}
+}
+
+func shouldTranslateHelpTextAndUsageText(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "--help"}
+	var strBuilder strings.Builder
+	catalog := cli.NewCatalog("de_DE.UTF-8")
+	catalog.AddMessages("de", cli.Messages{
+		cli.MsgUsageHeading:   "Verwendung:",
+		cli.MsgOptionsHeading: "Optionen:",
+		"print a greeting":    "einen Gruß ausgeben",
+	})
+	cmd := cli.NewCommand("testcmd", context.Background())
+	val := false
+	cmd.AddBoolArg(&val, &cli.ArgDefinition{Name: "greet", ShortName: 'g', UsageText: "print a greeting"})
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.SetCatalog(catalog)
+	runErr := cli.NewRunner(parser, "v1", &strBuilder).Run()
+	help := strBuilder.String()
+
+	if runErr != nil || !strings.HasPrefix(help, "Verwendung:") || !strings.Contains(help, "Optionen:") ||
+		!strings.Contains(help, "einen Gruß ausgeben") {
+		t.Fail()
+		t.Log(n + ": help text not translated: " + help)
+	}
+}
+
+func shouldNotShareDefaultMessages(t *testing.T, n string) {
+	catalog := cli.NewCatalog("en")
+	catalog.AddMessages("en", cli.Messages{cli.MsgUsageHeading: "Synopsis:"})
+
+	if catalog.Message(cli.MsgUsageHeading) != "Synopsis:" || cli.NewCatalog("en").Message(cli.MsgUsageHeading) != "Usage:" {
+		t.Fail()
+		t.Log(n + ": default messages shared between catalogs")
+	}
+}
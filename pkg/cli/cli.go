@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"bufio"
 	"context"
 	"io"
 )
@@ -12,148 +13,178 @@ const (
 	POSIX
 )
 
-type commandArg struct {
-	name       string
-	shortName  rune
-	usageText  string
-	repeatable bool
-	required   bool
-}
+type ColorMode int
 
-type boolArg struct {
-	*commandArg
-	value *bool
-}
+const (
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
 
-type float64Arg struct {
-	*commandArg
-	value *float64
-}
+type Style string
 
-type float64ListArg struct {
-	*commandArg
-	value *[]float64
+type Theme struct {
+	Command Style
+	Error   Style
+	Heading Style
+	Option  Style
+	Warning Style
 }
 
-type intArg struct {
-	*commandArg
-	value *int
-}
+type ExecutionMode int
 
-type intListArg struct {
-	*commandArg
-	value *[]int
-}
+const (
+	FullChain ExecutionMode = iota
+	LeafOnly
+	ChainedVerbs
+)
 
-type int64Arg struct {
-	*commandArg
-	value *int64
-}
+type argBinder func(a *parsedArg, m Catalog) error
 
-type int64ListArg struct {
-	*commandArg
-	value *[]int64
+type argConfig struct {
+	bind       argBinder
+	builtin    bool
+	Choices    []string
+	Name       string
+	Repeatable bool
+	Required   bool
+	Secret     bool
+	ShortName  rune
+	UsageText  string
+	Value      interface{}
 }
 
-type stringArg struct {
-	*commandArg
-	value *string
+type argIndex struct {
+	configs    []*argConfig
+	names      map[string]int
+	shortNames map[rune]int
 }
 
-type stringListArg struct {
-	*commandArg
-	value *[]string
+type operandConfig struct {
+	Choices   []string
+	File      *File
+	Name      string
+	Required  bool
+	UsageText string
+	Value     *string
 }
 
-type uintArg struct {
-	*commandArg
-	value *uint
-}
+type FileMode int
 
-type uintListArg struct {
-	*commandArg
-	value *[]uint
-}
+const (
+	FileRead FileMode = iota
+	FileWrite
+)
 
-type uint64Arg struct {
-	*commandArg
-	value *uint64
+type File struct {
+	closer io.Closer
+	Mode   FileMode
+	Open   bool
+	Path   string
+	Reader io.Reader
+	Writer io.Writer
 }
 
-type uint64ListArg struct {
-	*commandArg
-	value *[]uint64
-}
+type MessageID string
+
+type Messages map[MessageID]string
 
-type commandArgs struct {
-	boolArgs        []*boolArg
-	float64Args     []*float64Arg
-	float64ListArgs []*float64ListArg
-	intArgs         []*intArg
-	intListArgs     []*intListArg
-	int64Args       []*int64Arg
-	int64ListArgs   []*int64ListArg
-	stringArgs      []*stringArg
-	stringListArgs  []*stringListArg
-	uintArgs        []*uintArg
-	uintListArgs    []*uintListArg
-	uint64Args      []*uint64Arg
-	uint64ListArgs  []*uint64ListArg
+type Catalog interface {
+	AddMessages(l string, m Messages)
+	Error(id MessageID, v ...interface{}) error
+	Locale() string
+	Message(id MessageID, v ...interface{}) string
+	SetLocale(l string)
 }
 
-type argConfig struct {
-	Name       string
-	Repeatable bool
-	Required   bool
-	ShortName  rune
-	UsageText  string
-	Value      interface{}
+type catalog struct {
+	locale   string
+	messages map[string]Messages
 }
 
 type HelpFunc func(c *command, s ArgSyntax, w io.Writer) error
 
 type RunFunc func(ctx context.Context, o []string)
 
+type RunErrorFunc func(ctx context.Context, o []string) error
+
+type Handler func(ctx context.Context, o []string) error
+
+type Middleware func(next Handler) Handler
+
+type ParseHook func(ctx context.Context) error
+
 type command struct {
-	Args        []*argConfig
-	Context     context.Context
-	HelpFunc    HelpFunc
-	Name        string
-	Parent      *command
-	Operands    []string
-	Run         RunFunc
-	Subcommands []*command
+	argIndex        *argIndex
+	Args            []*argConfig
+	Catalog         Catalog
+	Context         context.Context
+	helpCommand     bool
+	HelpFunc        HelpFunc
+	Middleware      []Middleware
+	Name            string
+	Parent          *command
+	Operands        []*operandConfig
+	Plugins         []string
+	Run             Handler
+	subcommandIndex map[string]*command
+	Subcommands     []*command
+	Theme           *Theme
+	Topics          []*HelpTopic
+	UsageText       string
+}
+
+type plugin struct {
+	Args []string
+	Env  []string
+	Name string
+	Path string
 }
 
 type parsedArg struct {
-	bindVal  interface{}
-	name     string
-	rawArg   string
-	required bool
-	value    []string
+	bind      argBinder
+	bindVal   interface{}
+	builtin   bool
+	choices   []string
+	name      string
+	rawArg    string
+	required  bool
+	secret    bool
+	usageText string
+	value     []string
 }
 
 type parsedCommand struct {
-	args        []string
-	argConfigs  []*argConfig
-	command     *command
-	Context     context.Context
-	HelpCommand *command
-	HelpMode    bool
-	Name        string
-	Operands    []string
-	parsedArgs  []*parsedArg
-	Run         RunFunc
-	Subcommands []*parsedCommand
-	Syntax      ArgSyntax
-	VersionMode bool
+	args         []string
+	argIndex     *argIndex
+	Catalog      Catalog
+	Color        string
+	command      *command
+	Context      context.Context
+	HelpCommand  *command
+	HelpMode     bool
+	HelpTopic    *HelpTopic
+	Name         string
+	Operands     []string
+	OutputFormat string
+	parsedArgs   []*parsedArg
+	Plugin       *plugin
+	Run          Handler
+	SchemaMode   bool
+	Subcommands  []*parsedCommand
+	Syntax       ArgSyntax
+	VersionMode  bool
+	Warnings     []string
 }
 
 type argParserContext struct {
-	argConfigs      []*argConfig
+	argIndex        *argIndex
+	catalog         Catalog
 	lastParsedArg   *parsedArg
 	operands        []string
+	operandsAllowed bool
 	parsedArgs      []*parsedArg
+	parsedNames     map[string]bool
 	terminated      bool
 	terminatorIndex int
 }
@@ -163,8 +194,7 @@ type argParserRule func(a *string, i int, c *argParserContext) (bool, error)
 type argParserInit func(a []string) *argParserContext
 
 type commandWalker struct {
-	root *command
-	path []*command
+	current *command
 }
 
 type ArgDefinition struct {
@@ -173,12 +203,32 @@ type ArgDefinition struct {
 	UsageText  string
 	Repeatable bool
 	Required   bool
+	Choices    []string
+	Secret     bool
+}
+
+type HelpTopic struct {
+	Name      string
+	Text      string
+	UsageText string
+}
+
+type OperandDefinition struct {
+	Name      string
+	UsageText string
+	Required  bool
+	Choices   []string
 }
 
 type CommandBuilder interface {
 	AddSubcommand(c ...CommandBuilder)
 	AddRunFunc(r RunFunc)
+	AddRunErrorFunc(r RunErrorFunc)
+	AddMiddleware(m ...Middleware)
+	AddUsageText(u string)
+	AddHelpTopic(t ...*HelpTopic)
 	AddBoolArg(p *bool, a *ArgDefinition)
+	AddFileArg(p *File, a *ArgDefinition)
 	AddFloat64Arg(p *float64, a *ArgDefinition)
 	AddFloat64ListArg(p *[]float64, a *ArgDefinition)
 	AddIntArg(p *int, a *ArgDefinition)
@@ -191,35 +241,169 @@ type CommandBuilder interface {
 	AddUintListArg(p *[]uint, a *ArgDefinition)
 	AddUint64Arg(p *uint64, a *ArgDefinition)
 	AddUint64ListArg(p *[]uint64, a *ArgDefinition)
+	AddOperand(p *string, o *OperandDefinition)
+	AddFileOperand(p *File, o *OperandDefinition)
 	Build() *command
 }
 
 type commandBuilder struct {
-	args        *commandArgs
+	args        []*argConfig
 	ctx         context.Context
+	middleware  []Middleware
 	name        string
-	run         RunFunc
+	operands    []*operandConfig
+	topics      []*HelpTopic
+	run         Handler
 	subcommands []CommandBuilder
+	usageText   string
 }
 
 type Parser interface {
+	EnablePlugins(d ...string)
+	EnablePluginsFunc(f func() []string)
 	Parse() ([]*parsedCommand, error)
+	SetArgs(a []string)
+	SetCatalog(c Catalog)
+	SetEnv(e []string)
+	SetPrompter(p Prompter)
 }
 
 type parser struct {
+	args           []string
 	argSyntax      ArgSyntax
 	builder        CommandBuilder
+	catalog        Catalog
+	color          string
+	env            []string
 	HelpCommand    *command
 	helpMode       bool
+	outputFormat   string
+	outputSet      bool
 	parsedCommands []*parsedCommand
+	pluginDirs     func() []string
+	pluginsEnabled bool
+	promptArgs     []*parsedArg
+	prompter       Prompter
+	schemaMode     bool
+}
+
+type Prompt struct {
+	Choices   []string
+	Default   string
+	Name      string
+	Secret    bool
+	UsageText string
+}
+
+type Prompter interface {
+	Interactive() bool
+	Prompt(p *Prompt) (string, error)
+}
+
+type prompter struct {
+	catalog Catalog
+	input   io.Reader
+	reader  *bufio.Reader
+	writer  io.Writer
 }
 
 type Runner interface {
+	AddMiddleware(m ...Middleware)
+	AddParseHook(h ...ParseHook)
 	Run() error
+	RunContext(ctx context.Context) error
+	SetColorMode(m ColorMode)
+	SetEnv(e []string)
+	SetErrorWriter(w io.Writer)
+	SetExecutionMode(m ExecutionMode)
+	SetInput(r io.Reader)
+	SetTheme(t *Theme)
+	SetVersionInfo(i *VersionInfo)
+	SetVersionTemplate(t string)
 }
 
 type runner struct {
-	parser  Parser
-	version string
-	writer  io.Writer
+	colorMode       ColorMode
+	env             []string
+	errWriter       io.Writer
+	input           io.Reader
+	middleware      []Middleware
+	mode            ExecutionMode
+	parseHooks      []ParseHook
+	parser          Parser
+	theme           *Theme
+	versionInfo     *VersionInfo
+	versionTemplate string
+	writer          io.Writer
+}
+
+type VersionInfo struct {
+	Name         string           `json:"name"`
+	Version      string           `json:"version"`
+	Commit       string           `json:"commit,omitempty"`
+	BuildDate    string           `json:"buildDate,omitempty"`
+	Dirty        bool             `json:"dirty"`
+	GoVersion    string           `json:"goVersion"`
+	Dependencies []*ModuleVersion `json:"dependencies,omitempty"`
+}
+
+type ModuleVersion struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Sum     string `json:"sum,omitempty"`
+}
+
+type chainValuesKey struct{}
+
+type commandPathKey struct{}
+
+type stderrKey struct{}
+
+type stdinKey struct{}
+
+type stdoutKey struct{}
+
+type mergedContext struct {
+	context.Context
+	values context.Context
+}
+
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+type PanicError struct {
+	Command string
+	Stack   []byte
+	Value   interface{}
+}
+
+type CommandSchema struct {
+	Name        string           `json:"name"`
+	UsageText   string           `json:"usageText,omitempty"`
+	Options     []*OptionSchema  `json:"options"`
+	Operands    []*OperandSchema `json:"operands"`
+	Subcommands []*CommandSchema `json:"subcommands"`
+}
+
+type OptionSchema struct {
+	Name       string      `json:"name,omitempty"`
+	ShortName  string      `json:"shortName,omitempty"`
+	Type       string      `json:"type"`
+	Default    interface{} `json:"default,omitempty"`
+	Choices    []string    `json:"choices,omitempty"`
+	Repeatable bool        `json:"repeatable"`
+	Required   bool        `json:"required"`
+	Secret     bool        `json:"secret,omitempty"`
+	UsageText  string      `json:"usageText,omitempty"`
+}
+
+type OperandSchema struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Default   string   `json:"default,omitempty"`
+	Choices   []string `json:"choices,omitempty"`
+	Required  bool     `json:"required"`
+	UsageText string   `json:"usageText,omitempty"`
 }
@@ -0,0 +1,144 @@
+package clitest
+
+import (
+	"bytes"
+	"errors"
+	"github.com/sebuckler/teel/pkg/cli"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const UpdateGoldenEnv = "CLITEST_UPDATE_GOLDEN"
+
+type Config struct {
+	Args       []string
+	Catalog    cli.Catalog
+	Env        []string
+	Mode       cli.ExecutionMode
+	PluginDirs []string
+	Prompter   cli.Prompter
+	Stdin      io.Reader
+	Syntax     cli.ArgSyntax
+	Version    string
+}
+
+type Result struct {
+	Err      error
+	ExitCode int
+	Stderr   string
+	Stdout   string
+}
+
+func Run(b cli.CommandBuilder, c *Config) *Result {
+	if c == nil {
+		c = &Config{}
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	stdin := c.Stdin
+
+	if stdin == nil {
+		stdin = strings.NewReader("")
+	}
+
+	env := c.Env
+
+	if env == nil {
+		env = []string{}
+	}
+
+	args := c.Args
+
+	if args == nil {
+		args = []string{}
+	}
+
+	prompter := c.Prompter
+
+	if prompter == nil {
+		prompter = cli.NewPrompter(stdin, &stderr)
+	}
+
+	parser := cli.NewParser(c.Syntax, b)
+	parser.SetArgs(args)
+	parser.SetEnv(env)
+	parser.SetPrompter(prompter)
+
+	if c.Catalog != nil {
+		parser.SetCatalog(c.Catalog)
+	}
+
+	if c.PluginDirs != nil {
+		parser.EnablePlugins(c.PluginDirs...)
+	}
+
+	runner := cli.NewRunner(parser, c.Version, &stdout)
+	runner.SetEnv(env)
+	runner.SetErrorWriter(&stderr)
+	runner.SetExecutionMode(c.Mode)
+	runner.SetInput(stdin)
+	runErr := runner.Run()
+
+	return &Result{
+		Err:      runErr,
+		ExitCode: getExitCode(runErr),
+		Stderr:   stderr.String(),
+		Stdout:   stdout.String(),
+	}
+}
+
+func AssertGolden(t testing.TB, p string, a string) {
+	t.Helper()
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if mkdirErr := os.MkdirAll(filepath.Dir(p), 0755); mkdirErr != nil {
+			t.Fatal(mkdirErr)
+		}
+
+		if writeErr := ioutil.WriteFile(p, []byte(a), 0644); writeErr != nil {
+			t.Fatal(writeErr)
+		}
+
+		return
+	}
+
+	expected, readErr := ioutil.ReadFile(p)
+
+	if readErr != nil {
+		t.Fatal(readErr.Error() + " (set " + UpdateGoldenEnv + "=1 to create it)")
+	}
+
+	if string(expected) != a {
+		t.Errorf("output does not match golden file %s\n--- expected\n%s\n--- actual\n%s", p, expected, a)
+	}
+}
+
+func AssertHelpGolden(t testing.TB, b cli.CommandBuilder, p string, c ...string) {
+	t.Helper()
+	result := Run(b, &Config{Args: append(append([]string{}, c...), "--help")})
+
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	AssertGolden(t, p, result.Stdout)
+}
+
+func getExitCode(err error) int {
+	var exitErr *cli.ExitError
+
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	if err != nil {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,130 @@
+package clitest_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/sebuckler/teel/pkg/cli"
+	"github.com/sebuckler/teel/pkg/cli/clitest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	for name, test := range getRunTestCases() {
+		test(t, name)
+	}
+}
+
+func getRunTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should capture stdout of run command":      shouldCaptureStdoutOfRunCommand,
+		"should capture stderr of run command":      shouldCaptureStderrOfRunCommand,
+		"should report exit code of failed parse":   shouldReportExitCodeOfFailedParse,
+		"should fail fast when stdin is not a tty":  shouldFailFastWhenStdinIsNotATty,
+		"should answer prompts with prompter":       shouldAnswerPromptsWithPrompter,
+		"should match help output to golden file":   shouldMatchHelpOutputToGoldenFile,
+		"should run in parallel with isolated args": shouldRunInParallelWithIsolatedArgs,
+	}
+}
+
+func newTestCommand() cli.CommandBuilder {
+	name := "world"
+	cmd := cli.NewCommand("greet", context.Background())
+	cmd.AddUsageText("print a greeting")
+	cmd.AddStringArg(&name, &cli.ArgDefinition{Name: "name", ShortName: 'n', UsageText: "who to greet"})
+	cmd.AddRunFunc(func(ctx context.Context, _ []string) {
+		_, _ = fmt.Fprint(cli.Stdout(ctx), "hello "+name)
+
+		if name == "" {
+			_, _ = fmt.Fprint(cli.Stderr(ctx), "nobody to greet")
+		}
+	})
+
+	return cmd
+}
+
+func shouldCaptureStdoutOfRunCommand(t *testing.T, n string) {
+	result := clitest.Run(newTestCommand(), &clitest.Config{Args: []string{"--name=teel"}})
+
+	if result.Err != nil || result.ExitCode != 0 || result.Stdout != "hello teel" || result.Stderr != "" {
+		t.Fail()
+		t.Log(n + ": failed to capture command output")
+	}
+}
+
+func shouldCaptureStderrOfRunCommand(t *testing.T, n string) {
+	result := clitest.Run(newTestCommand(), &clitest.Config{Args: []string{"--name="}})
+
+	if result.Err != nil || result.Stdout != "hello " || result.Stderr != "nobody to greet" {
+		t.Fail()
+		t.Log(n + ": failed to capture command error output")
+	}
+}
+
+func shouldReportExitCodeOfFailedParse(t *testing.T, n string) {
+	result := clitest.Run(newTestCommand(), &clitest.Config{Args: []string{"--unknown"}})
+
+	if result.Err == nil || result.ExitCode != 1 {
+		t.Fail()
+		t.Log(n + ": did not report failed parse")
+	}
+}
+
+type answerPrompter struct {
+	answer string
+}
+
+func (p *answerPrompter) Interactive() bool {
+	return true
+}
+
+func (p *answerPrompter) Prompt(*cli.Prompt) (string, error) {
+	return p.answer, nil
+}
+
+func shouldFailFastWhenStdinIsNotATty(t *testing.T, n string) {
+	title := ""
+	cmd := cli.NewCommand("page", context.Background())
+	cmd.AddOperand(&title, &cli.OperandDefinition{Name: "title", Required: true})
+	result := clitest.Run(cmd, &clitest.Config{Stdin: strings.NewReader("My Page\n")})
+
+	if result.Err == nil || title != "" {
+		t.Fail()
+		t.Log(n + ": prompted without an interactive terminal")
+	}
+}
+
+func shouldAnswerPromptsWithPrompter(t *testing.T, n string) {
+	title := ""
+	cmd := cli.NewCommand("page", context.Background())
+	cmd.AddOperand(&title, &cli.OperandDefinition{Name: "title", Required: true})
+	result := clitest.Run(cmd, &clitest.Config{Prompter: &answerPrompter{answer: "My Page"}})
+
+	if result.Err != nil || title != "My Page" {
+		t.Fail()
+		t.Log(n + ": did not answer prompt")
+	}
+}
+
+func shouldMatchHelpOutputToGoldenFile(t *testing.T, _ string) {
+	clitest.AssertHelpGolden(t, newTestCommand(), "testdata/help.golden")
+}
+
+func shouldRunInParallelWithIsolatedArgs(t *testing.T, n string) {
+	t.Run(n, func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			name := strconv.Itoa(i)
+
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				result := clitest.Run(newTestCommand(), &clitest.Config{Args: []string{"--name=" + name}})
+
+				if result.Err != nil || result.Stdout != "hello "+name {
+					t.Fail()
+					t.Log(fmt.Sprintf("%s: run %s received output '%s'", n, name, result.Stdout))
+				}
+			})
+		}
+	})
+}
@@ -9,14 +9,6 @@ import (
 
 func NewCommand(n string, c context.Context) CommandBuilder {
 	return &commandBuilder{
-		args: &commandArgs{
-			boolArgs:   []*boolArg{},
-			intArgs:    []*intArg{},
-			int64Args:  []*int64Arg{},
-			stringArgs: []*stringArg{},
-			uintArgs:   []*uintArg{},
-			uint64Args: []*uint64Arg{},
-		},
 		ctx:         c,
 		name:        n,
 		subcommands: []CommandBuilder{},
@@ -24,7 +16,33 @@ func NewCommand(n string, c context.Context) CommandBuilder {
 }
 
 func (b *commandBuilder) AddRunFunc(r RunFunc) {
-	b.run = r
+	if r == nil {
+		b.run = nil
+
+		return
+	}
+
+	b.run = func(ctx context.Context, o []string) error {
+		r(ctx, o)
+
+		return nil
+	}
+}
+
+func (b *commandBuilder) AddRunErrorFunc(r RunErrorFunc) {
+	b.run = Handler(r)
+}
+
+func (b *commandBuilder) AddMiddleware(m ...Middleware) {
+	b.middleware = append(b.middleware, m...)
+}
+
+func (b *commandBuilder) AddUsageText(u string) {
+	b.usageText = u
+}
+
+func (b *commandBuilder) AddHelpTopic(t ...*HelpTopic) {
+	b.topics = append(b.topics, t...)
 }
 
 func (b *commandBuilder) AddSubcommand(cmd ...CommandBuilder) {
@@ -32,94 +50,73 @@ func (b *commandBuilder) AddSubcommand(cmd ...CommandBuilder) {
 }
 
 func (b *commandBuilder) AddBoolArg(p *bool, a *ArgDefinition) {
-	b.args.boolArgs = append(b.args.boolArgs, &boolArg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindBoolArg))
+}
+
+func (b *commandBuilder) AddFileArg(p *File, a *ArgDefinition) {
+	b.args = append(b.args, newArgConfig(a, p, bindFileArg))
 }
 
 func (b *commandBuilder) AddFloat64Arg(p *float64, a *ArgDefinition) {
-	b.args.float64Args = append(b.args.float64Args, &float64Arg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindFloat64Arg))
 }
 
 func (b *commandBuilder) AddFloat64ListArg(p *[]float64, a *ArgDefinition) {
-	b.args.float64ListArgs = append(b.args.float64ListArgs, &float64ListArg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindFloat64ListArg))
 }
 
 func (b *commandBuilder) AddIntArg(p *int, a *ArgDefinition) {
-	b.args.intArgs = append(b.args.intArgs, &intArg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindIntArg))
 }
 
 func (b *commandBuilder) AddIntListArg(p *[]int, a *ArgDefinition) {
-	b.args.intListArgs = append(b.args.intListArgs, &intListArg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindIntListArg))
 }
 
 func (b *commandBuilder) AddInt64Arg(p *int64, a *ArgDefinition) {
-	b.args.int64Args = append(b.args.int64Args, &int64Arg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindInt64Arg))
 }
 
 func (b *commandBuilder) AddInt64ListArg(p *[]int64, a *ArgDefinition) {
-	b.args.int64ListArgs = append(b.args.int64ListArgs, &int64ListArg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindInt64ListArg))
 }
 
 func (b *commandBuilder) AddStringArg(p *string, a *ArgDefinition) {
-	b.args.stringArgs = append(b.args.stringArgs, &stringArg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindStringArg))
 }
 
 func (b *commandBuilder) AddStringListArg(p *[]string, a *ArgDefinition) {
-	b.args.stringListArgs = append(b.args.stringListArgs, &stringListArg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindStringListArg))
 }
 
 func (b *commandBuilder) AddUintArg(p *uint, a *ArgDefinition) {
-	b.args.uintArgs = append(b.args.uintArgs, &uintArg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindUintArg))
 }
 
 func (b *commandBuilder) AddUintListArg(p *[]uint, a *ArgDefinition) {
-	b.args.uintListArgs = append(b.args.uintListArgs, &uintListArg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindUintListArg))
 }
 
 func (b *commandBuilder) AddUint64Arg(p *uint64, a *ArgDefinition) {
-	b.args.uint64Args = append(b.args.uint64Args, &uint64Arg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindUint64Arg))
 }
 
 func (b *commandBuilder) AddUint64ListArg(p *[]uint64, a *ArgDefinition) {
-	b.args.uint64ListArgs = append(b.args.uint64ListArgs, &uint64ListArg{
-		commandArg: newCommandArg(a),
-		value:      p,
-	})
+	b.args = append(b.args, newArgConfig(a, p, bindUint64ListArg))
+}
+
+func (b *commandBuilder) AddOperand(p *string, o *OperandDefinition) {
+	operand := newOperandConfig(o)
+	operand.Value = p
+
+	b.operands = append(b.operands, operand)
+}
+
+func (b *commandBuilder) AddFileOperand(p *File, o *OperandDefinition) {
+	operand := newOperandConfig(o)
+	operand.File = p
+
+	b.operands = append(b.operands, operand)
 }
 
 func (b *commandBuilder) Build() *command {
@@ -130,9 +127,13 @@ func (b *commandBuilder) Build() *command {
 		Args:        argConfigs,
 		Context:     b.ctx,
 		HelpFunc:    b.configureHelpFunc(argConfigs),
+		Middleware:  b.middleware,
 		Name:        b.name,
+		Operands:    b.operands,
 		Run:         b.run,
 		Subcommands: subcommands,
+		Topics:      b.topics,
+		UsageText:   b.usageText,
 	}
 
 	for _, subCmd := range command.Subcommands {
@@ -143,25 +144,18 @@ func (b *commandBuilder) Build() *command {
 }
 
 func (b *commandBuilder) configureArgs() []*argConfig {
-	var argConfigs []*argConfig
+	argConfigs := make([]*argConfig, 0, len(b.args)+3)
+	colorArgExists := false
 	helpArgConfigExists := false
 	versionArgExists := false
 
-	argConfigs = append(argConfigs, b.configureBoolArgs()...)
-	argConfigs = append(argConfigs, b.configureFloat64Args()...)
-	argConfigs = append(argConfigs, b.configureFloat64ListArgs()...)
-	argConfigs = append(argConfigs, b.configureIntArgs()...)
-	argConfigs = append(argConfigs, b.configureIntListArgs()...)
-	argConfigs = append(argConfigs, b.configureInt64Args()...)
-	argConfigs = append(argConfigs, b.configureInt64ListArgs()...)
-	argConfigs = append(argConfigs, b.configureStringArgs()...)
-	argConfigs = append(argConfigs, b.configureStringListArgs()...)
-	argConfigs = append(argConfigs, b.configureUintArgs()...)
-	argConfigs = append(argConfigs, b.configureUintListArgs()...)
-	argConfigs = append(argConfigs, b.configureUint64Args()...)
-	argConfigs = append(argConfigs, b.configureUint64ListArgs()...)
+	argConfigs = append(argConfigs, b.args...)
 
 	for _, argConfig := range argConfigs {
+		if argConfig.Name == "color" {
+			colorArgExists = true
+		}
+
 		if argConfig.Name == "help" || argConfig.Name == "h" || argConfig.ShortName == 'h' {
 			helpArgConfigExists = true
 		}
@@ -172,8 +166,9 @@ func (b *commandBuilder) configureArgs() []*argConfig {
 	}
 
 	if !helpArgConfigExists {
-		val := true
+		val := false
 		argConfigs = append(argConfigs, &argConfig{
+			bind:       bindBoolArg,
 			Name:       "help",
 			Repeatable: true,
 			ShortName:  'h',
@@ -183,8 +178,9 @@ func (b *commandBuilder) configureArgs() []*argConfig {
 	}
 
 	if !versionArgExists {
-		val := true
+		val := false
 		argConfigs = append(argConfigs, &argConfig{
+			bind:       bindBoolArg,
 			Name:       "version",
 			Repeatable: true,
 			ShortName:  'v',
@@ -193,6 +189,19 @@ func (b *commandBuilder) configureArgs() []*argConfig {
 		})
 	}
 
+	if !colorArgExists {
+		val := ""
+		argConfigs = append(argConfigs, &argConfig{
+			bind:       bindStringArg,
+			builtin:    true,
+			Choices:    []string{"auto", "always", "never"},
+			Name:       "color",
+			Repeatable: true,
+			UsageText:  "colourise output: auto, always or never",
+			Value:      &val,
+		})
+	}
+
 	return argConfigs
 }
 
@@ -210,10 +219,21 @@ func (b *commandBuilder) getHelpTemplate(c *command, s ArgSyntax, a []*argConfig
 	var parentNames []string
 	var parentUsage string
 	longestArgLine := float64(0)
+	longestName := float64(0)
 	var argLines [][]string
+	m := getCatalog(c.Catalog)
+	t := getTheme(c.Theme)
+
+	for _, cmd := range c.Subcommands {
+		longestName = math.Max(float64(len(cmd.Name)), longestName)
+	}
+
+	for _, topic := range c.Topics {
+		longestName = math.Max(float64(len(topic.Name)), longestName)
+	}
 
 	for parent != nil {
-		parentNames = append(parentNames, c.Parent.Name)
+		parentNames = append([]string{parent.Name}, parentNames...)
 		parent = parent.Parent
 	}
 
@@ -221,24 +241,75 @@ func (b *commandBuilder) getHelpTemplate(c *command, s ArgSyntax, a []*argConfig
 		parentUsage = strings.Join(parentNames, " ") + " "
 	}
 
-	helpBuilder.WriteString(`Usage:
+	helpBuilder.WriteString(t.Heading.Apply(m.Message(MsgUsageHeading)) + `
     ` + parentUsage + b.name)
 
 	if len(c.Subcommands) > 0 {
-		helpBuilder.WriteString(` [command]
+		helpBuilder.WriteString(` ` + m.Message(MsgCommandPlaceholder) + `
 
-Commands:
+` + t.Heading.Apply(m.Message(MsgCommandsHeading)) + `
 `)
 	}
 
 	for _, cmd := range c.Subcommands {
-		helpBuilder.WriteString(strings.Repeat(" ", 4) + cmd.Name)
+		helpBuilder.WriteString(strings.Repeat(" ", 4) + t.Command.Apply(cmd.Name))
+
+		if cmd.UsageText != "" {
+			helpBuilder.WriteString(strings.Repeat(" ", int(longestName)-len(cmd.Name)+4))
+			helpBuilder.WriteString(translateUsage(m, cmd.UsageText))
+		}
+
+		helpBuilder.WriteString(`
+`)
 	}
 
-	if len(a) > 0 {
+	if len(c.Plugins) > 0 {
+		if len(c.Subcommands) == 0 {
+			helpBuilder.WriteString(`
+`)
+		}
+
 		helpBuilder.WriteString(`
+` + t.Heading.Apply(m.Message(MsgPluginsHeading)) + `
+`)
+	}
 
-Options:
+	for _, pluginName := range c.Plugins {
+		helpBuilder.WriteString(strings.Repeat(" ", 4) + t.Command.Apply(pluginName) + `
+`)
+	}
+
+	if len(c.Topics) > 0 {
+		if len(c.Subcommands) == 0 && len(c.Plugins) == 0 {
+			helpBuilder.WriteString(`
+`)
+		}
+
+		helpBuilder.WriteString(`
+` + t.Heading.Apply(m.Message(MsgHelpTopicsHeading)) + `
+`)
+	}
+
+	for _, topic := range c.Topics {
+		helpBuilder.WriteString(strings.Repeat(" ", 4) + t.Command.Apply(topic.Name))
+
+		if topic.UsageText != "" {
+			helpBuilder.WriteString(strings.Repeat(" ", int(longestName)-len(topic.Name)+4))
+			helpBuilder.WriteString(translateUsage(m, topic.UsageText))
+		}
+
+		helpBuilder.WriteString(`
+`)
+	}
+
+	if len(a) > 0 {
+		if len(c.Subcommands) == 0 && len(c.Plugins) == 0 && len(c.Topics) == 0 {
+			helpBuilder.WriteString(`
+`)
+		}
+
+		helpBuilder.WriteString(`
+` + t.Heading.Apply(m.Message(MsgOptionsHeading)) + `
     `)
 	}
 
@@ -253,7 +324,7 @@ Options:
 
 			if arg.Name != "" {
 				if argLine == "" {
-					argLine = "-" + string(arg.Name[0]) + ", "
+					argLine = "    "
 				}
 
 				argLine += "--" + arg.Name
@@ -273,11 +344,11 @@ Options:
 		}
 
 		longestArgLine = math.Max(float64(len(argLine)), longestArgLine)
-		argLines = append(argLines, []string{argLine, arg.UsageText})
+		argLines = append(argLines, []string{argLine, translateUsage(m, arg.UsageText)})
 	}
 
 	for i, argLine := range argLines {
-		helpBuilder.WriteString(argLine[0])
+		helpBuilder.WriteString(t.Option.Apply(argLine[0]))
 		helpBuilder.WriteString(strings.Repeat(" ", int(longestArgLine)-len(argLine[0])+4))
 		helpBuilder.WriteString(argLine[1] + `
 `)
@@ -287,150 +358,24 @@ Options:
 		}
 	}
 
-	return helpBuilder.String()
-}
-
-func (b *commandBuilder) configureBoolArgs() []*argConfig {
-	var boolArgConfigs []*argConfig
-
-	for _, arg := range b.args.boolArgs {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		boolArgConfigs = append(boolArgConfigs, argConfig)
-	}
-
-	return boolArgConfigs
-}
-
-func (b *commandBuilder) configureFloat64Args() []*argConfig {
-	var float64ArgConfigs []*argConfig
-
-	for _, arg := range b.args.float64Args {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		float64ArgConfigs = append(float64ArgConfigs, argConfig)
-	}
-
-	return float64ArgConfigs
-}
-
-func (b *commandBuilder) configureFloat64ListArgs() []*argConfig {
-	var float64ListArgConfigs []*argConfig
-
-	for _, arg := range b.args.float64ListArgs {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		float64ListArgConfigs = append(float64ListArgConfigs, argConfig)
-	}
-
-	return float64ListArgConfigs
-}
-
-func (b *commandBuilder) configureIntArgs() []*argConfig {
-	var intArgConfigs []*argConfig
-
-	for _, arg := range b.args.intArgs {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		intArgConfigs = append(intArgConfigs, argConfig)
-	}
-
-	return intArgConfigs
-}
-
-func (b *commandBuilder) configureIntListArgs() []*argConfig {
-	var intListArgConfigs []*argConfig
-
-	for _, arg := range b.args.intListArgs {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		intListArgConfigs = append(intListArgConfigs, argConfig)
-	}
-
-	return intListArgConfigs
-}
-
-func (b *commandBuilder) configureInt64Args() []*argConfig {
-	var int64ArgConfigs []*argConfig
-
-	for _, arg := range b.args.int64Args {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		int64ArgConfigs = append(int64ArgConfigs, argConfig)
-	}
-
-	return int64ArgConfigs
-}
-
-func (b *commandBuilder) configureInt64ListArgs() []*argConfig {
-	var int64ListArgConfigs []*argConfig
-
-	for _, arg := range b.args.int64ListArgs {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		int64ListArgConfigs = append(int64ListArgConfigs, argConfig)
-	}
-
-	return int64ListArgConfigs
-}
-
-func (b *commandBuilder) configureStringArgs() []*argConfig {
-	var stringArgConfigs []*argConfig
-
-	for _, arg := range b.args.stringArgs {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		stringArgConfigs = append(stringArgConfigs, argConfig)
-	}
-
-	return stringArgConfigs
-}
-
-func (b *commandBuilder) configureStringListArgs() []*argConfig {
-	var stringListArgConfigs []*argConfig
-
-	for _, arg := range b.args.stringListArgs {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		stringListArgConfigs = append(stringListArgConfigs, argConfig)
-	}
-
-	return stringListArgConfigs
-}
-
-func (b *commandBuilder) configureUintArgs() []*argConfig {
-	var uintArgConfigs []*argConfig
-
-	for _, arg := range b.args.uintArgs {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		uintArgConfigs = append(uintArgConfigs, argConfig)
-	}
-
-	return uintArgConfigs
-}
-
-func (b *commandBuilder) configureUintListArgs() []*argConfig {
-	var uintListArgConfigs []*argConfig
-
-	for _, arg := range b.args.uintListArgs {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		uintListArgConfigs = append(uintListArgConfigs, argConfig)
-	}
-
-	return uintListArgConfigs
-}
-
-func (b *commandBuilder) configureUint64Args() []*argConfig {
-	var uint64ArgConfigs []*argConfig
-
-	for _, arg := range b.args.uint64Args {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		uint64ArgConfigs = append(uint64ArgConfigs, argConfig)
+	if len(c.Operands) > 0 {
+		helpBuilder.WriteString(`
+` + t.Heading.Apply(m.Message(MsgOperandsHeading)) + `
+`)
 	}
 
-	return uint64ArgConfigs
-}
+	for _, operand := range c.Operands {
+		helpBuilder.WriteString(strings.Repeat(" ", 4) + t.Option.Apply(operand.Name))
 
-func (b *commandBuilder) configureUint64ListArgs() []*argConfig {
-	var uint64ListArgConfigs []*argConfig
+		if operand.UsageText != "" {
+			helpBuilder.WriteString(strings.Repeat(" ", 4) + translateUsage(m, operand.UsageText))
+		}
 
-	for _, arg := range b.args.uint64ListArgs {
-		argConfig := newArgConfig(arg.commandArg, arg.value)
-		uint64ListArgConfigs = append(uint64ListArgConfigs, argConfig)
+		helpBuilder.WriteString(`
+`)
 	}
 
-	return uint64ListArgConfigs
+	return helpBuilder.String()
 }
 
 func (b *commandBuilder) configureSubcommands() []*command {
@@ -443,27 +388,33 @@ func (b *commandBuilder) configureSubcommands() []*command {
 	return subcommandConfigs
 }
 
-func newCommandArg(a *ArgDefinition) *commandArg {
+func newArgConfig(a *ArgDefinition, v interface{}, b argBinder) *argConfig {
 	if a == nil {
-		return &commandArg{}
+		return &argConfig{bind: b, Value: v}
 	}
 
-	return &commandArg{
-		name:       a.Name,
-		shortName:  a.ShortName,
-		usageText:  a.UsageText,
-		repeatable: a.Repeatable,
-		required:   a.Required,
+	return &argConfig{
+		bind:       b,
+		Choices:    a.Choices,
+		Name:       a.Name,
+		Repeatable: a.Repeatable,
+		Required:   a.Required,
+		Secret:     a.Secret,
+		ShortName:  a.ShortName,
+		UsageText:  a.UsageText,
+		Value:      v,
 	}
 }
 
-func newArgConfig(a *commandArg, v interface{}) *argConfig {
-	return &argConfig{
-		Name:       a.name,
-		Repeatable: a.repeatable,
-		Required:   a.required,
-		ShortName:  a.shortName,
-		UsageText:  a.usageText,
-		Value:      v,
+func newOperandConfig(o *OperandDefinition) *operandConfig {
+	if o == nil {
+		return &operandConfig{}
+	}
+
+	return &operandConfig{
+		Choices:   o.Choices,
+		Name:      o.Name,
+		Required:  o.Required,
+		UsageText: o.UsageText,
 	}
 }
@@ -438,7 +438,7 @@ func shouldHaveOnlyHelpCommandWhenNoOtherArgDefined(t *testing.T, n string) {
 	cmdBuilder.AddBoolArg(nil, nil)
 	command := cmdBuilder.Build()
 
-	if len(command.Args) > 3 || (command.Args[1].Name != "help" && command.Args[2].Name != "version") {
+	if len(command.Args) > 4 || (command.Args[1].Name != "help" && command.Args[2].Name != "version") {
 		t.Fail()
 		t.Log(n + ": help arg not configured")
 	}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const StdioPath = "-"
+
+func (f File) String() string {
+	return f.Path
+}
+
+func (f *File) IsStdio() bool {
+	return f.Path == StdioPath
+}
+
+func setFileValue(f *File, v string, m Catalog) error {
+	if v == "" {
+		return m.Error(MsgMissingFilePath)
+	}
+
+	if v != StdioPath {
+		if fileErr := checkFile(v, f.Mode, m); fileErr != nil {
+			return fileErr
+		}
+	}
+
+	f.Path = v
+
+	return nil
+}
+
+func checkFile(p string, f FileMode, m Catalog) error {
+	info, statErr := os.Stat(p)
+
+	if f == FileWrite && os.IsNotExist(statErr) {
+		dir, dirErr := os.Stat(filepath.Dir(p))
+
+		if dirErr != nil || !dir.IsDir() {
+			return m.Error(MsgDirectoryNotFound, filepath.Dir(p))
+		}
+
+		return nil
+	}
+
+	if os.IsNotExist(statErr) {
+		return m.Error(MsgFileNotFound, p)
+	}
+
+	if statErr != nil {
+		return m.Error(MsgFileNotAccessible, p)
+	}
+
+	if info.IsDir() {
+		return m.Error(MsgFileIsDirectory, p)
+	}
+
+	flag := os.O_RDONLY
+	msg := MsgFileNotReadable
+
+	if f == FileWrite {
+		flag = os.O_WRONLY
+		msg = MsgFileNotWritable
+	}
+
+	file, openErr := os.OpenFile(p, flag, 0)
+
+	if openErr != nil {
+		return m.Error(msg, p)
+	}
+
+	return file.Close()
+}
+
+func getFiles(c []*parsedCommand) []*File {
+	var files []*File
+
+	for _, cmd := range c {
+		for _, arg := range cmd.command.Args {
+			if file, ok := arg.Value.(*File); ok && file.Open && file.Path != "" {
+				files = append(files, file)
+			}
+		}
+
+		for _, operand := range cmd.command.Operands {
+			if operand.File != nil && operand.File.Open && operand.File.Path != "" {
+				files = append(files, operand.File)
+			}
+		}
+	}
+
+	return files
+}
+
+func openFiles(f []*File, r io.Reader, w io.Writer) error {
+	for _, file := range f {
+		if file.IsStdio() {
+			file.Reader = r
+			file.Writer = w
+
+			continue
+		}
+
+		if file.Mode == FileWrite {
+			osFile, createErr := os.Create(file.Path)
+
+			if createErr != nil {
+				return createErr
+			}
+
+			file.closer = osFile
+			file.Writer = osFile
+
+			continue
+		}
+
+		osFile, openErr := os.Open(file.Path)
+
+		if openErr != nil {
+			return openErr
+		}
+
+		file.closer = osFile
+		file.Reader = osFile
+	}
+
+	return nil
+}
+
+func closeFiles(f []*File) error {
+	var closeErr error
+
+	for _, file := range f {
+		if file.closer == nil {
+			continue
+		}
+
+		if err := file.closer.Close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+
+		file.closer = nil
+	}
+
+	return closeErr
+}
@@ -0,0 +1,224 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/sebuckler/teel/pkg/cli"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFile(t *testing.T) {
+	for name, test := range getFileTestCases() {
+		test(t, name)
+	}
+}
+
+func getFileTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should open file arg for reading":           shouldOpenFileArgForReading,
+		"should read stdin for dash file operand":    shouldReadStdinForDashFileOperand,
+		"should write stdout for dash file arg":      shouldWriteStdoutForDashFileArg,
+		"should create file arg for writing":         shouldCreateFileArgForWriting,
+		"should error on missing read file":          shouldErrorOnMissingReadFile,
+		"should error on directory file":             shouldErrorOnDirectoryFile,
+		"should error on missing write directory":    shouldErrorOnMissingWriteDirectory,
+		"should not open file when open is false":    shouldNotOpenFileWhenOpenIsFalse,
+		"should close files after run":               shouldCloseFilesAfterRun,
+		"should not open files when help arg exists": shouldNotOpenFilesWhenHelpArgExists,
+	}
+}
+
+func createTestFileDir(t *testing.T, n string) string {
+	dir, dirErr := ioutil.TempDir("", "cli-file")
+
+	if dirErr != nil {
+		t.Fatal(n + ": failed to create temp dir")
+	}
+
+	if writeErr := ioutil.WriteFile(filepath.Join(dir, "in.txt"), []byte("hello"), 0644); writeErr != nil {
+		t.Fatal(n + ": failed to write temp file")
+	}
+
+	return dir
+}
+
+func runFileTestCommand(a []string, s string, w *bytes.Buffer, b func(c cli.CommandBuilder)) error {
+	cmd := cli.NewCommand("testcmd", context.Background())
+	b(cmd)
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.SetArgs(a)
+	parser.SetEnv([]string{})
+	runner := cli.NewRunner(parser, "v1", w)
+	runner.SetErrorWriter(&bytes.Buffer{})
+	runner.SetInput(strings.NewReader(s))
+
+	return runner.Run()
+}
+
+func shouldOpenFileArgForReading(t *testing.T, n string) {
+	dir := createTestFileDir(t, n)
+	defer os.RemoveAll(dir)
+	in := &cli.File{Mode: cli.FileRead, Open: true}
+	content := ""
+	runErr := runFileTestCommand([]string{"--in=" + filepath.Join(dir, "in.txt")}, "", &bytes.Buffer{}, func(c cli.CommandBuilder) {
+		c.AddFileArg(in, &cli.ArgDefinition{Name: "in", Required: true})
+		c.AddRunFunc(func(context.Context, []string) {
+			data, _ := ioutil.ReadAll(in.Reader)
+			content = string(data)
+		})
+	})
+
+	if runErr != nil || content != "hello" {
+		t.Fail()
+		t.Log(n + ": failed to read file arg")
+	}
+}
+
+func shouldReadStdinForDashFileOperand(t *testing.T, n string) {
+	in := &cli.File{Mode: cli.FileRead, Open: true}
+	content := ""
+	runErr := runFileTestCommand([]string{"--", "-"}, "from stdin", &bytes.Buffer{}, func(c cli.CommandBuilder) {
+		c.AddFileOperand(in, &cli.OperandDefinition{Name: "input", Required: true})
+		c.AddRunFunc(func(context.Context, []string) {
+			data, _ := ioutil.ReadAll(in.Reader)
+			content = string(data)
+		})
+	})
+
+	if runErr != nil || content != "from stdin" || !in.IsStdio() {
+		t.Fail()
+		t.Log(n + ": failed to read stdin for dash operand")
+	}
+}
+
+func shouldWriteStdoutForDashFileArg(t *testing.T, n string) {
+	var stdout bytes.Buffer
+	out := &cli.File{Mode: cli.FileWrite, Open: true}
+	runErr := runFileTestCommand([]string{"--out=-"}, "", &stdout, func(c cli.CommandBuilder) {
+		c.AddFileArg(out, &cli.ArgDefinition{Name: "out", Required: true})
+		c.AddRunFunc(func(context.Context, []string) {
+			_, _ = out.Writer.Write([]byte("to stdout"))
+		})
+	})
+
+	if runErr != nil || stdout.String() != "to stdout" {
+		t.Fail()
+		t.Log(n + ": failed to write stdout for dash arg")
+	}
+}
+
+func shouldCreateFileArgForWriting(t *testing.T, n string) {
+	dir := createTestFileDir(t, n)
+	defer os.RemoveAll(dir)
+	out := &cli.File{Mode: cli.FileWrite, Open: true}
+	path := filepath.Join(dir, "out.txt")
+	runErr := runFileTestCommand([]string{"--out=" + path}, "", &bytes.Buffer{}, func(c cli.CommandBuilder) {
+		c.AddFileArg(out, &cli.ArgDefinition{Name: "out", Required: true})
+		c.AddRunFunc(func(context.Context, []string) {
+			_, _ = out.Writer.Write([]byte("written"))
+		})
+	})
+	data, readErr := ioutil.ReadFile(path)
+
+	if runErr != nil || readErr != nil || string(data) != "written" {
+		t.Fail()
+		t.Log(n + ": failed to write file arg")
+	}
+}
+
+func shouldErrorOnMissingReadFile(t *testing.T, n string) {
+	dir := createTestFileDir(t, n)
+	defer os.RemoveAll(dir)
+	in := &cli.File{Mode: cli.FileRead}
+	runErr := runFileTestCommand([]string{"--in=" + filepath.Join(dir, "missing.txt")}, "", &bytes.Buffer{}, func(c cli.CommandBuilder) {
+		c.AddFileArg(in, &cli.ArgDefinition{Name: "in", Required: true})
+		c.AddRunFunc(func(context.Context, []string) {
+			t.Fail()
+			t.Log(n + ": should not have run command")
+		})
+	})
+
+	if runErr == nil || !strings.Contains(runErr.Error(), "file not found") {
+		t.Fail()
+		t.Log(n + ": did not error on missing file")
+	}
+}
+
+func shouldErrorOnDirectoryFile(t *testing.T, n string) {
+	dir := createTestFileDir(t, n)
+	defer os.RemoveAll(dir)
+	in := &cli.File{Mode: cli.FileRead}
+	runErr := runFileTestCommand([]string{"--", dir}, "", &bytes.Buffer{}, func(c cli.CommandBuilder) {
+		c.AddFileOperand(in, &cli.OperandDefinition{Name: "input", Required: true})
+	})
+
+	if runErr == nil || !strings.Contains(runErr.Error(), "is a directory") {
+		t.Fail()
+		t.Log(n + ": did not error on directory")
+	}
+}
+
+func shouldErrorOnMissingWriteDirectory(t *testing.T, n string) {
+	dir := createTestFileDir(t, n)
+	defer os.RemoveAll(dir)
+	out := &cli.File{Mode: cli.FileWrite}
+	runErr := runFileTestCommand([]string{"--out=" + filepath.Join(dir, "missing", "out.txt")}, "", &bytes.Buffer{}, func(c cli.CommandBuilder) {
+		c.AddFileArg(out, &cli.ArgDefinition{Name: "out", Required: true})
+	})
+
+	if runErr == nil || !strings.Contains(runErr.Error(), "directory not found") {
+		t.Fail()
+		t.Log(n + ": did not error on missing directory")
+	}
+}
+
+func shouldNotOpenFileWhenOpenIsFalse(t *testing.T, n string) {
+	dir := createTestFileDir(t, n)
+	defer os.RemoveAll(dir)
+	in := &cli.File{Mode: cli.FileRead}
+	path := filepath.Join(dir, "in.txt")
+	runErr := runFileTestCommand([]string{"--in=" + path}, "", &bytes.Buffer{}, func(c cli.CommandBuilder) {
+		c.AddFileArg(in, &cli.ArgDefinition{Name: "in", Required: true})
+	})
+
+	if runErr != nil || in.Reader != nil || in.Path != path {
+		t.Fail()
+		t.Log(n + ": opened file when open is false")
+	}
+}
+
+func shouldCloseFilesAfterRun(t *testing.T, n string) {
+	dir := createTestFileDir(t, n)
+	defer os.RemoveAll(dir)
+	out := &cli.File{Mode: cli.FileWrite, Open: true}
+	runErr := runFileTestCommand([]string{"--out=" + filepath.Join(dir, "out.txt")}, "", &bytes.Buffer{}, func(c cli.CommandBuilder) {
+		c.AddFileArg(out, &cli.ArgDefinition{Name: "out", Required: true})
+		c.AddRunFunc(func(context.Context, []string) {})
+	})
+	_, writeErr := out.Writer.Write([]byte("after close"))
+
+	if runErr != nil || writeErr == nil {
+		t.Fail()
+		t.Log(n + ": did not close file after run")
+	}
+}
+
+func shouldNotOpenFilesWhenHelpArgExists(t *testing.T, n string) {
+	dir := createTestFileDir(t, n)
+	defer os.RemoveAll(dir)
+	out := &cli.File{Mode: cli.FileWrite, Open: true}
+	path := filepath.Join(dir, "out.txt")
+	runErr := runFileTestCommand([]string{"--out=" + path, "--help"}, "", &bytes.Buffer{}, func(c cli.CommandBuilder) {
+		c.AddFileArg(out, &cli.ArgDefinition{Name: "out", Required: true})
+	})
+	_, statErr := os.Stat(path)
+
+	if runErr != nil || !os.IsNotExist(statErr) {
+		t.Fail()
+		t.Log(n + ": opened file in help mode")
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/sebuckler/teel/pkg/cli
 
-go 1.14
+go 1.18
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"io"
+	"strings"
+)
+
+const HelpCommandName = "help"
+
+func withHelpCommand(c *command) *command {
+	if (len(c.Subcommands) == 0 && len(c.Topics) == 0) || c.getSubcommand(HelpCommandName) != nil {
+		return c
+	}
+
+	builder := NewCommand(HelpCommandName, c.Context)
+	builder.AddUsageText("display help for a command or topic")
+	helpCmd := builder.Build()
+	helpCmd.helpCommand = true
+	helpCmd.Parent = c
+	c.Subcommands = append(c.Subcommands, helpCmd)
+	c.subcommandIndex[HelpCommandName] = helpCmd
+
+	return c
+}
+
+func resolveHelpPath(c *command, p []string, m Catalog) (*command, *HelpTopic, error) {
+	if len(p) == 1 {
+		for _, topic := range c.Topics {
+			if topic.Name == p[0] {
+				return nil, topic, nil
+			}
+		}
+	}
+
+	resolved := c
+
+	for _, name := range p {
+		found := resolved.getSubcommand(name)
+
+		if found == nil || found.helpCommand {
+			return nil, nil, getUnknownHelpError(resolved, name, m)
+		}
+
+		resolved = found
+	}
+
+	return resolved, nil, nil
+}
+
+func getUnknownHelpError(c *command, n string, m Catalog) error {
+	var candidates []string
+
+	for _, cmd := range c.Subcommands {
+		if !cmd.helpCommand {
+			candidates = append(candidates, cmd.Name)
+		}
+	}
+
+	if c.Parent == nil {
+		for _, topic := range c.Topics {
+			candidates = append(candidates, topic.Name)
+		}
+	}
+
+	if suggestion := getSuggestion(n, candidates); suggestion != "" {
+		return m.Error(MsgSuggestedTopic, n, suggestion)
+	}
+
+	return m.Error(MsgUnknownTopic, n)
+}
+
+func getSuggestion(n string, c []string) string {
+	suggestion := ""
+	bestDistance := len(n)/3 + 1
+
+	for _, candidate := range c {
+		if strings.HasPrefix(candidate, n) && len(n) > 1 {
+			return candidate
+		}
+
+		if distance := getEditDistance(n, candidate); distance <= bestDistance {
+			if distance < bestDistance || suggestion == "" {
+				suggestion = candidate
+			}
+
+			bestDistance = distance
+		}
+	}
+
+	return suggestion
+}
+
+func getEditDistance(a string, b string) int {
+	source := []rune(a)
+	target := []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func writeHelpTopic(t *HelpTopic, m Catalog, w io.Writer) error {
+	text := translateUsage(m, t.Text)
+
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	_, err := io.WriteString(w, text)
+
+	return err
+}
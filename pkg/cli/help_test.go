@@ -0,0 +1,177 @@
+package cli_test
+
+import (
+	"context"
+	"github.com/sebuckler/teel/pkg/cli"
+	"strings"
+	"testing"
+)
+
+func TestHelp(t *testing.T) {
+	for name, test := range getHelpTestCases() {
+		test(t, name)
+	}
+}
+
+func getHelpTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should print root help for help command":       shouldPrintRootHelpForHelpCommand,
+		"should print subcommand help for help path":    shouldPrintSubcommandHelpForHelpPath,
+		"should print help topic text":                  shouldPrintHelpTopicText,
+		"should list help topics in root help":          shouldListHelpTopicsInRootHelp,
+		"should suggest similar help topic":             shouldSuggestSimilarHelpTopic,
+		"should error on unknown help path":             shouldErrorOnUnknownHelpPath,
+		"should suggest similar command":                shouldSuggestSimilarCommand,
+		"should not add help command without commands":  shouldNotAddHelpCommandWithoutCommands,
+		"should not replace user defined help command":  shouldNotReplaceUserDefinedHelpCommand,
+		"should not invent short names for gnu options": shouldNotInventShortNamesForGnuOptions,
+		"should align command and topic usage":          shouldAlignCommandAndTopicUsage,
+	}
+}
+
+func newHelpTestCommand() cli.CommandBuilder {
+	cmd := cli.NewCommand("testcmd", context.Background())
+	page := cli.NewCommand("page", context.Background())
+	page.AddUsageText("manage pages")
+	newPage := cli.NewCommand("new", context.Background())
+	newPage.AddUsageText("create a page")
+	page.AddSubcommand(newPage)
+	cmd.AddSubcommand(page)
+	cmd.AddHelpTopic(&cli.HelpTopic{
+		Name:      "templates",
+		Text:      "Templates are rendered with Go's text/template package.",
+		UsageText: "how page templates work",
+	})
+
+	return cmd
+}
+
+func runHelpTestCommand(c cli.CommandBuilder, a []string) (string, string, error) {
+	var stdout strings.Builder
+	var stderr strings.Builder
+	parser := cli.NewParser(cli.GNU, c)
+	parser.SetArgs(a)
+	parser.SetEnv([]string{})
+	runner := cli.NewRunner(parser, "v1", &stdout)
+	runner.SetErrorWriter(&stderr)
+	runErr := runner.Run()
+
+	return stdout.String(), stderr.String(), runErr
+}
+
+func shouldPrintRootHelpForHelpCommand(t *testing.T, n string) {
+	expected, _, _ := runHelpTestCommand(newHelpTestCommand(), []string{"--help"})
+	actual, _, runErr := runHelpTestCommand(newHelpTestCommand(), []string{"help"})
+
+	if runErr != nil || actual != expected || !strings.Contains(actual, "help         display help for a command or topic") {
+		t.Fail()
+		t.Log(n + ": failed to print root help")
+	}
+}
+
+func shouldPrintSubcommandHelpForHelpPath(t *testing.T, n string) {
+	expected, _, _ := runHelpTestCommand(newHelpTestCommand(), []string{"page", "new", "--help"})
+	actual, _, runErr := runHelpTestCommand(newHelpTestCommand(), []string{"help", "page", "new"})
+
+	if runErr != nil || actual != expected || !strings.Contains(actual, "testcmd page new") {
+		t.Fail()
+		t.Log(n + ": failed to print subcommand help")
+	}
+}
+
+func shouldPrintHelpTopicText(t *testing.T, n string) {
+	actual, _, runErr := runHelpTestCommand(newHelpTestCommand(), []string{"help", "templates"})
+
+	if runErr != nil || actual != "Templates are rendered with Go's text/template package.\n" {
+		t.Fail()
+		t.Log(n + ": failed to print help topic")
+	}
+}
+
+func shouldListHelpTopicsInRootHelp(t *testing.T, n string) {
+	actual, _, runErr := runHelpTestCommand(newHelpTestCommand(), []string{"--help"})
+
+	if runErr != nil || !strings.Contains(actual, "Help topics:\n    templates    how page templates work\n") {
+		t.Fail()
+		t.Log(n + ": failed to list help topics")
+	}
+}
+
+func shouldSuggestSimilarHelpTopic(t *testing.T, n string) {
+	_, stderr, runErr := runHelpTestCommand(newHelpTestCommand(), []string{"help", "tempaltes"})
+
+	if runErr == nil || !strings.Contains(stderr, "did you mean templates?") {
+		t.Fail()
+		t.Log(n + ": failed to suggest help topic")
+	}
+}
+
+func shouldErrorOnUnknownHelpPath(t *testing.T, n string) {
+	_, stderr, runErr := runHelpTestCommand(newHelpTestCommand(), []string{"help", "page", "zzz"})
+
+	if runErr == nil || !strings.Contains(stderr, "unknown help topic or command: zzz") || strings.Contains(stderr, "did you mean") {
+		t.Fail()
+		t.Log(n + ": did not error on unknown help path")
+	}
+}
+
+func shouldSuggestSimilarCommand(t *testing.T, n string) {
+	_, stderr, runErr := runHelpTestCommand(newHelpTestCommand(), []string{"pgae"})
+
+	if runErr == nil || !strings.Contains(stderr, "unknown command: pgae (did you mean page?)") {
+		t.Fail()
+		t.Log(n + ": failed to suggest command")
+	}
+}
+
+func shouldNotAddHelpCommandWithoutCommands(t *testing.T, n string) {
+	actual, _, runErr := runHelpTestCommand(cli.NewCommand("testcmd", context.Background()), []string{"--help"})
+
+	if runErr != nil || strings.Contains(actual, "Commands:") {
+		t.Fail()
+		t.Log(n + ": added help command without commands")
+	}
+}
+
+func shouldNotReplaceUserDefinedHelpCommand(t *testing.T, n string) {
+	ran := false
+	cmd := newHelpTestCommand()
+	help := cli.NewCommand("help", context.Background())
+	help.AddRunFunc(func(context.Context, []string) {
+		ran = true
+	})
+	cmd.AddSubcommand(help)
+	_, _, runErr := runHelpTestCommand(cmd, []string{"help"})
+
+	if runErr != nil || !ran {
+		t.Fail()
+		t.Log(n + ": replaced user defined help command")
+	}
+}
+
+func shouldNotInventShortNamesForGnuOptions(t *testing.T, n string) {
+	cmd := cli.NewCommand("testcmd", context.Background())
+	logFile := ""
+	cmd.AddStringArg(&logFile, &cli.ArgDefinition{Name: "log-file", UsageText: "write logs to file"})
+	stdout, _, runErr := runHelpTestCommand(cmd, []string{"--help"})
+
+	if runErr != nil || !strings.Contains(stdout, "\n        --log-file    ") || strings.Contains(stdout, "-l, ") {
+		t.Fail()
+		t.Log(n + ": long-only option rendered incorrectly: " + stdout)
+	}
+}
+
+func shouldAlignCommandAndTopicUsage(t *testing.T, n string) {
+	actual, _, runErr := runHelpTestCommand(newHelpTestCommand(), []string{"--help"})
+	expected := "Commands:\n" +
+		"    page         manage pages\n" +
+		"    help         display help for a command or topic\n" +
+		"\n" +
+		"Help topics:\n" +
+		"    templates    how page templates work\n"
+
+	if runErr != nil || !strings.Contains(actual, expected) {
+		t.Fail()
+		t.Log(n + ": usage text not aligned: " + actual)
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+func newArgIndex(a []*argConfig) *argIndex {
+	index := &argIndex{
+		configs:    a,
+		names:      make(map[string]int, len(a)),
+		shortNames: map[rune]int{},
+	}
+
+	for i, argConfig := range a {
+		if _, exists := index.names[argConfig.Name]; !exists && argConfig.Name != "" {
+			index.names[argConfig.Name] = i
+		}
+
+		if _, exists := index.shortNames[argConfig.ShortName]; !exists && argConfig.ShortName > 0 {
+			index.shortNames[argConfig.ShortName] = i
+		}
+	}
+
+	return index
+}
+
+func (i *argIndex) find(n string, s rune) *argConfig {
+	found := -1
+
+	if position, ok := i.names[n]; ok {
+		found = position
+	}
+
+	if position, ok := i.shortNames[s]; ok && s > 0 && (found < 0 || position < found) {
+		found = position
+	}
+
+	if found < 0 {
+		return nil
+	}
+
+	return i.configs[found]
+}
+
+func (c *command) getArgIndex() *argIndex {
+	if c.argIndex == nil {
+		c.argIndex = newArgIndex(withBuiltinArgConfigs(c.Args))
+	}
+
+	return c.argIndex
+}
+
+func (c *command) getSubcommand(n string) *command {
+	if c.subcommandIndex == nil {
+		c.subcommandIndex = newSubcommandIndex(c.Subcommands)
+	}
+
+	return c.subcommandIndex[n]
+}
+
+func newSubcommandIndex(c []*command) map[string]*command {
+	index := make(map[string]*command, len(c))
+
+	for _, cmd := range c {
+		if _, exists := index[cmd.Name]; !exists {
+			index[cmd.Name] = cmd
+		}
+	}
+
+	return index
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"context"
+	"strings"
+)
+
+func CommandPath(ctx context.Context) string {
+	path, _ := ctx.Value(commandPathKey{}).(string)
+
+	return path
+}
+
+func (r *runner) getHandler(c *parsedCommand) Handler {
+	handler := c.Run
+	middleware := append([]Middleware{}, r.middleware...)
+	middleware = append(middleware, getCommandMiddleware(c.command)...)
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
+
+func getCommandMiddleware(c *command) []Middleware {
+	var middleware []Middleware
+
+	for cmd := c; cmd != nil; cmd = cmd.Parent {
+		middleware = append(append([]Middleware{}, cmd.Middleware...), middleware...)
+	}
+
+	return middleware
+}
+
+func getCommandPath(c *command) string {
+	var names []string
+
+	for cmd := c; cmd != nil; cmd = cmd.Parent {
+		names = append([]string{cmd.Name}, names...)
+	}
+
+	return strings.Join(names, " ")
+}
+
+func mergeContext(v context.Context, c context.Context) context.Context {
+	if v == nil || v == c {
+		return c
+	}
+
+	return &mergedContext{
+		Context: c,
+		values:  v,
+	}
+}
+
+func (c *mergedContext) Value(k interface{}) interface{} {
+	if value := c.values.Value(k); value != nil {
+		return value
+	}
+
+	return c.Context.Value(k)
+}
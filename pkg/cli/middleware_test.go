@@ -0,0 +1,207 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"github.com/sebuckler/teel/pkg/cli"
+	"strings"
+	"testing"
+)
+
+type middlewareTestKey struct{}
+
+func TestMiddleware(t *testing.T) {
+	for name, test := range getMiddlewareTestCases() {
+		test(t, name)
+	}
+}
+
+func getMiddlewareTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should run middleware in defined order":    shouldRunMiddlewareInDefinedOrder,
+		"should return middleware error":            shouldReturnMiddlewareError,
+		"should skip run when middleware stops":     shouldSkipRunWhenMiddlewareStops,
+		"should pass middleware context to run":     shouldPassMiddlewareContextToRun,
+		"should expose command path in context":     shouldExposeCommandPathInContext,
+		"should not wrap commands without run func": shouldNotWrapCommandsWithoutRunFunc,
+		"should run parse hooks before help":        shouldRunParseHooksBeforeHelp,
+		"should run parse hooks after parse error":  shouldRunParseHooksAfterParseError,
+	}
+}
+
+func newTraceMiddleware(n string, t *[]string) cli.Middleware {
+	return func(next cli.Handler) cli.Handler {
+		return func(ctx context.Context, o []string) error {
+			*t = append(*t, n+":before")
+			err := next(ctx, o)
+			*t = append(*t, n+":after")
+
+			return err
+		}
+	}
+}
+
+func runMiddlewareTestCommand(c cli.CommandBuilder, a []string, m ...cli.Middleware) error {
+	parser := cli.NewParser(cli.GNU, c)
+	parser.SetArgs(a)
+	parser.SetEnv([]string{})
+	runner := cli.NewRunner(parser, "v1", &strings.Builder{})
+	runner.SetErrorWriter(&strings.Builder{})
+	runner.SetExecutionMode(cli.LeafOnly)
+	runner.AddMiddleware(m...)
+
+	return runner.Run()
+}
+
+func shouldRunMiddlewareInDefinedOrder(t *testing.T, n string) {
+	var trace []string
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddMiddleware(newTraceMiddleware("root1", &trace), newTraceMiddleware("root2", &trace))
+	sub := cli.NewCommand("sub", context.Background())
+	sub.AddMiddleware(newTraceMiddleware("sub", &trace))
+	sub.AddRunFunc(func(context.Context, []string) {
+		trace = append(trace, "run")
+	})
+	cmd.AddSubcommand(sub)
+	runErr := runMiddlewareTestCommand(cmd, []string{"sub"}, newTraceMiddleware("runner", &trace))
+	expected := "runner:before,root1:before,root2:before,sub:before,run," +
+		"sub:after,root2:after,root1:after,runner:after"
+
+	if runErr != nil || strings.Join(trace, ",") != expected {
+		t.Fail()
+		t.Log(n + ": unexpected middleware order: " + strings.Join(trace, ","))
+	}
+}
+
+func shouldReturnMiddlewareError(t *testing.T, n string) {
+	expected := errors.New("middleware failed")
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddRunFunc(func(context.Context, []string) {})
+	runErr := runMiddlewareTestCommand(cmd, []string{}, func(next cli.Handler) cli.Handler {
+		return func(ctx context.Context, o []string) error {
+			_ = next(ctx, o)
+
+			return expected
+		}
+	})
+
+	if !errors.Is(runErr, expected) {
+		t.Fail()
+		t.Log(n + ": did not return middleware error")
+	}
+}
+
+func shouldSkipRunWhenMiddlewareStops(t *testing.T, n string) {
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddRunFunc(func(context.Context, []string) {
+		t.Fail()
+		t.Log(n + ": should not have run command")
+	})
+	cmd.AddMiddleware(func(cli.Handler) cli.Handler {
+		return func(context.Context, []string) error {
+			return nil
+		}
+	})
+
+	if runErr := runMiddlewareTestCommand(cmd, []string{}); runErr != nil {
+		t.Fail()
+		t.Log(n + ": unexpected error")
+	}
+}
+
+func shouldPassMiddlewareContextToRun(t *testing.T, n string) {
+	value := ""
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddRunFunc(func(ctx context.Context, _ []string) {
+		value, _ = ctx.Value(middlewareTestKey{}).(string)
+	})
+	cmd.AddMiddleware(func(next cli.Handler) cli.Handler {
+		return func(ctx context.Context, o []string) error {
+			return next(context.WithValue(ctx, middlewareTestKey{}, "injected"), o)
+		}
+	})
+
+	if runErr := runMiddlewareTestCommand(cmd, []string{}); runErr != nil || value != "injected" {
+		t.Fail()
+		t.Log(n + ": did not pass middleware context to run")
+	}
+}
+
+func shouldExposeCommandPathInContext(t *testing.T, n string) {
+	path := ""
+	cmd := cli.NewCommand("testcmd", context.Background())
+	sub := cli.NewCommand("sub", context.Background())
+	sub.AddRunFunc(func(context.Context, []string) {})
+	cmd.AddSubcommand(sub)
+	runErr := runMiddlewareTestCommand(cmd, []string{"sub"}, func(next cli.Handler) cli.Handler {
+		return func(ctx context.Context, o []string) error {
+			path = cli.CommandPath(ctx)
+
+			return next(ctx, o)
+		}
+	})
+
+	if runErr != nil || path != "testcmd sub" {
+		t.Fail()
+		t.Log(n + ": did not expose command path")
+	}
+}
+
+func shouldNotWrapCommandsWithoutRunFunc(t *testing.T, n string) {
+	var trace []string
+	cmd := cli.NewCommand("testcmd", context.Background())
+
+	if runErr := runMiddlewareTestCommand(cmd, []string{}, newTraceMiddleware("runner", &trace)); runErr != nil || len(trace) > 0 {
+		t.Fail()
+		t.Log(n + ": wrapped command without run func")
+	}
+}
+
+func shouldRunParseHooksBeforeHelp(t *testing.T, n string) {
+	var trace []string
+	cmd := cli.NewCommand("testcmd", context.Background())
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.SetArgs([]string{"--help"})
+	runner := cli.NewRunner(parser, "v1", &traceWriter{trace: &trace})
+	runner.AddParseHook(func(context.Context) error {
+		trace = append(trace, "hook")
+
+		return nil
+	})
+	runErr := runner.Run()
+
+	if runErr != nil || len(trace) != 2 || trace[0] != "hook" || trace[1] != "write" {
+		t.Fail()
+		t.Log(n + ": parse hook did not run before help: " + strings.Join(trace, ","))
+	}
+}
+
+func shouldRunParseHooksAfterParseError(t *testing.T, n string) {
+	hookRun := false
+	cmd := cli.NewCommand("testcmd", context.Background())
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.SetArgs([]string{"--unknown"})
+	runner := cli.NewRunner(parser, "v1", &strings.Builder{})
+	runner.SetErrorWriter(&strings.Builder{})
+	runner.AddParseHook(func(context.Context) error {
+		hookRun = true
+
+		return errors.New("hook failed")
+	})
+	runErr := runner.Run()
+
+	if !hookRun || runErr == nil || strings.Contains(runErr.Error(), "hook failed") {
+		t.Fail()
+		t.Log(n + ": parse hook did not run or replaced parse error")
+	}
+}
+
+type traceWriter struct {
+	trace *[]string
+}
+
+func (w *traceWriter) Write(p []byte) (int, error) {
+	*w.trace = append(*w.trace, "write")
+
+	return len(p), nil
+}
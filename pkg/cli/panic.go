@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+func NewPanicError(c string, v interface{}, s []byte) *PanicError {
+	return &PanicError{
+		Command: c,
+		Stack:   s,
+		Value:   v,
+	}
+}
+
+func (e *PanicError) Error() string {
+	if e.Command == "" {
+		return fmt.Sprintf("panic: %v", e.Value)
+	}
+
+	return fmt.Sprintf("panic in %s: %v", e.Command, e.Value)
+}
+
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+
+	return err
+}
+
+func recoverPanic(e *error, c string) {
+	if v := recover(); v != nil {
+		*e = NewPanicError(c, v, debug.Stack())
+	}
+}
@@ -0,0 +1,113 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"github.com/sebuckler/teel/pkg/cli"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunner_Panic(t *testing.T) {
+	for name, test := range getPanicTestCases() {
+		test(t, name)
+	}
+}
+
+func getPanicTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should recover run func panic as panic error": shouldRecoverRunFuncPanicAsPanicError,
+		"should recover middleware panic":              shouldRecoverMiddlewarePanic,
+		"should unwrap panic error value":              shouldUnwrapPanicErrorValue,
+		"should close files after panic":               shouldCloseFilesAfterPanic,
+	}
+}
+
+func runPanicTestCommand(c cli.CommandBuilder, a []string, m ...cli.Middleware) (string, error) {
+	var stderr strings.Builder
+	parser := cli.NewParser(cli.GNU, c)
+	parser.SetArgs(a)
+	parser.SetEnv([]string{})
+	runner := cli.NewRunner(parser, "v1", &strings.Builder{})
+	runner.SetErrorWriter(&stderr)
+	runner.SetExecutionMode(cli.LeafOnly)
+	runner.AddMiddleware(m...)
+	runErr := runner.Run()
+
+	return stderr.String(), runErr
+}
+
+func shouldRecoverRunFuncPanicAsPanicError(t *testing.T, n string) {
+	cmd := cli.NewCommand("testcmd", context.Background())
+	sub := cli.NewCommand("sub", context.Background())
+	sub.AddRunFunc(func(context.Context, []string) {
+		panic("boom")
+	})
+	cmd.AddSubcommand(sub)
+	stderr, runErr := runPanicTestCommand(cmd, []string{"sub"})
+	var panicErr *cli.PanicError
+	var exitErr *cli.ExitError
+
+	if !errors.As(runErr, &panicErr) || !errors.As(runErr, &exitErr) || exitErr.Code != 1 ||
+		panicErr.Command != "testcmd sub" || panicErr.Value != "boom" ||
+		!strings.Contains(string(panicErr.Stack), "panic_test.go") ||
+		stderr != "Error: panic in testcmd sub: boom\n" {
+		t.Fail()
+		t.Log(n + ": failed to recover run func panic")
+	}
+}
+
+func shouldRecoverMiddlewarePanic(t *testing.T, n string) {
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddRunFunc(func(context.Context, []string) {})
+	_, runErr := runPanicTestCommand(cmd, []string{}, func(cli.Handler) cli.Handler {
+		return func(context.Context, []string) error {
+			panic("middleware boom")
+		}
+	})
+	var panicErr *cli.PanicError
+
+	if !errors.As(runErr, &panicErr) || panicErr.Value != "middleware boom" {
+		t.Fail()
+		t.Log(n + ": failed to recover middleware panic")
+	}
+}
+
+func shouldUnwrapPanicErrorValue(t *testing.T, n string) {
+	expected := errors.New("wrapped")
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddRunFunc(func(context.Context, []string) {
+		panic(expected)
+	})
+
+	if _, runErr := runPanicTestCommand(cmd, []string{}); !errors.Is(runErr, expected) {
+		t.Fail()
+		t.Log(n + ": failed to unwrap panic error value")
+	}
+}
+
+func shouldCloseFilesAfterPanic(t *testing.T, n string) {
+	dir, dirErr := ioutil.TempDir("", "cli-panic")
+
+	if dirErr != nil {
+		t.Fatal(n + ": failed to create temp dir")
+	}
+
+	defer os.RemoveAll(dir)
+	out := &cli.File{Mode: cli.FileWrite, Open: true}
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddFileArg(out, &cli.ArgDefinition{Name: "out", Required: true})
+	cmd.AddRunFunc(func(context.Context, []string) {
+		panic("boom")
+	})
+	_, runErr := runPanicTestCommand(cmd, []string{"--out=" + filepath.Join(dir, "out.txt")})
+	_, writeErr := out.Writer.Write([]byte("after panic"))
+
+	if runErr == nil || writeErr == nil {
+		t.Fail()
+		t.Log(n + ": did not close file after panic")
+	}
+}
@@ -1,8 +1,8 @@
 package cli
 
 import (
-	"errors"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -12,12 +12,57 @@ func NewParser(a ArgSyntax, c CommandBuilder) Parser {
 		argSyntax:      a,
 		builder:        c,
 		parsedCommands: []*parsedCommand{},
+		prompter:       NewPrompter(os.Stdin, os.Stderr),
 	}
 }
 
+func (p *parser) EnablePlugins(d ...string) {
+	p.EnablePluginsFunc(func() []string {
+		return d
+	})
+}
+
+func (p *parser) EnablePluginsFunc(f func() []string) {
+	p.pluginDirs = f
+	p.pluginsEnabled = true
+}
+
+func (p *parser) SetArgs(a []string) {
+	p.args = a
+}
+
+func (p *parser) SetCatalog(c Catalog) {
+	p.catalog = c
+}
+
+func (p *parser) SetEnv(e []string) {
+	p.env = e
+}
+
+func (p *parser) SetPrompter(r Prompter) {
+	p.prompter = r
+}
+
 func (p *parser) Parse() ([]*parsedCommand, error) {
-	args := os.Args[1:]
-	rootCmd := p.parseCommands(args, p.builder.Build())
+	args := p.args
+
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	if p.catalog == nil {
+		p.catalog = NewCatalog(DetectLocale(p.env))
+	}
+
+	if localized, ok := p.prompter.(interface{ SetCatalog(c Catalog) }); ok {
+		localized.SetCatalog(p.catalog)
+	}
+
+	rootCmd := p.parseCommands(args, p.configureCatalog(withHelpCommand(p.builder.Build())))
+
+	if rootCmd.Plugin != nil {
+		return p.parsedCommands, nil
+	}
 
 	for _, cmd := range p.parsedCommands {
 		if argErr := p.parseArgs(cmd); argErr != nil {
@@ -25,9 +70,62 @@ func (p *parser) Parse() ([]*parsedCommand, error) {
 		}
 	}
 
+	rootCmd.Color = p.color
+
+	if helpErr := p.resolveHelpCommand(rootCmd); helpErr != nil {
+		return nil, helpErr
+	}
+
+	if rootCmd.HelpTopic != nil {
+		return p.parsedCommands, nil
+	}
+
+	if p.schemaMode {
+		rootCmd.SchemaMode = p.schemaMode
+		rootCmd.HelpCommand = p.HelpCommand
+
+		return p.parsedCommands, nil
+	}
+
 	if p.helpMode {
 		rootCmd.HelpMode = p.helpMode
 		rootCmd.HelpCommand = p.HelpCommand
+
+		if p.pluginsEnabled && p.HelpCommand == rootCmd.command {
+			rootCmd.command.Plugins = listPlugins(rootCmd.Name+"-", p.getPluginSearchDirs())
+		}
+
+		return p.parsedCommands, nil
+	}
+
+	rootCmd.OutputFormat = p.outputFormat
+
+	for _, cmd := range p.parsedCommands {
+		if cmd.VersionMode {
+			rootCmd.VersionMode = true
+
+			return p.parsedCommands, nil
+		}
+	}
+
+	if p.outputSet {
+		rootCmd.Warnings = append(rootCmd.Warnings, p.catalog.Message(MsgOutputIgnored))
+	}
+
+	for _, arg := range p.promptArgs {
+		if promptErr := p.promptArgValue(arg); promptErr != nil {
+			return nil, promptErr
+		}
+
+		if bindErr := p.bindArgValue(arg); bindErr != nil {
+			return nil, bindErr
+		}
+	}
+
+	for _, cmd := range p.parsedCommands {
+		if operandErr := p.bindOperands(cmd); operandErr != nil {
+			return nil, operandErr
+		}
 	}
 
 	return p.parsedCommands, nil
@@ -43,12 +141,24 @@ func (p *parser) parseCommands(a []string, c *command) *parsedCommand {
 		return rootCmd
 	}
 
-	for _, arg := range a {
+	if pluginCmd := p.findPlugin(a, rootCmd); pluginCmd != nil {
+		rootCmd.Plugin = pluginCmd
+
+		return rootCmd
+	}
+
+	for i, arg := range a {
 		if found := walker.Walk(arg); found != nil {
 			parsed := p.newParsedCommand(found)
 			p.addParsedCommand(parsed)
 			lastParsed = parsed
 
+			if found.helpCommand {
+				parsed.Operands = append([]string{}, a[i+1:]...)
+
+				break
+			}
+
 			continue
 		}
 
@@ -58,10 +168,83 @@ func (p *parser) parseCommands(a []string, c *command) *parsedCommand {
 	return rootCmd
 }
 
+func (p *parser) findPlugin(a []string, r *parsedCommand) *plugin {
+	if !p.pluginsEnabled {
+		return nil
+	}
+
+	nameIndex := getPluginNameIndex(a, r.argIndex)
+
+	if nameIndex < 0 || r.command.getSubcommand(a[nameIndex]) != nil {
+		return nil
+	}
+
+	if nameIndex > 0 && !p.parseRootPluginArgs(a[:nameIndex], r) {
+		return nil
+	}
+
+	pluginPath, found := findPlugin(r.Name+"-", a[nameIndex], p.getPluginSearchDirs())
+
+	if !found {
+		return nil
+	}
+
+	return &plugin{
+		Args: a[nameIndex+1:],
+		Env:  p.env,
+		Name: a[nameIndex],
+		Path: pluginPath,
+	}
+}
+
+func (p *parser) parseRootPluginArgs(a []string, r *parsedCommand) bool {
+	r.args = append([]string{}, a...)
+	parseErr := p.parseArgs(r)
+	parsed := parseErr == nil && !p.helpMode && !p.schemaMode && !r.VersionMode
+
+	p.promptArgs = nil
+	r.args = []string{}
+	r.Operands = nil
+	r.parsedArgs = nil
+
+	if !parsed {
+		p.color = ""
+		p.helpMode = false
+		p.HelpCommand = nil
+		p.outputFormat = ""
+		p.outputSet = false
+		p.schemaMode = false
+		r.VersionMode = false
+	}
+
+	return parsed
+}
+
+func (p *parser) configureCatalog(c *command) *command {
+	c.Catalog = p.catalog
+
+	for _, subCmd := range c.Subcommands {
+		p.configureCatalog(subCmd)
+	}
+
+	return c
+}
+
+func (p *parser) getPluginSearchDirs() []string {
+	var dirs []string
+
+	if p.pluginDirs != nil {
+		dirs = append(dirs, p.pluginDirs()...)
+	}
+
+	return append(dirs, filepath.SplitList(getenv(p.env, "PATH"))...)
+}
+
 func (p *parser) newParsedCommand(c *command) *parsedCommand {
 	return &parsedCommand{
 		args:        []string{},
-		argConfigs:  c.Args,
+		argIndex:    c.getArgIndex(),
+		Catalog:     p.catalog,
 		command:     c,
 		Context:     c.Context,
 		HelpCommand: c,
@@ -82,13 +265,17 @@ func (p *parser) addParsedCommand(c *parsedCommand) {
 }
 
 func (p *parser) parseArgs(c *parsedCommand) error {
+	if cmdErr := p.checkUnknownCommand(c); cmdErr != nil {
+		return cmdErr
+	}
+
 	switch p.argSyntax {
 	case GNU:
 		return p.parseArgRules(c, getGnuRules(), getPosixArgParserContext)
 	case POSIX:
 		return p.parseArgRules(c, getPosixRules(), getPosixArgParserContext)
 	default:
-		return errors.New("unsupported argument parsing syntax")
+		return p.catalog.Error(MsgUnsupportedSyntax)
 	}
 }
 
@@ -98,7 +285,9 @@ func (p *parser) parseArgRules(c *parsedCommand, r []argParserRule, i argParserI
 	}
 
 	context := i(c.args)
-	context.argConfigs = c.argConfigs
+	context.argIndex = c.argIndex
+	context.catalog = p.catalog
+	context.operandsAllowed = len(c.command.Operands) > 0
 
 	for argIndex, arg := range c.args {
 		var skip bool
@@ -120,7 +309,7 @@ func (p *parser) parseArgRules(c *parsedCommand, r []argParserRule, i argParserI
 			continue
 		}
 
-		return errors.New("failed to parse argument: " + arg)
+		return p.catalog.Error(MsgFailedToParseArgument, arg)
 	}
 
 	c.parsedArgs = context.parsedArgs
@@ -129,6 +318,49 @@ func (p *parser) parseArgRules(c *parsedCommand, r []argParserRule, i argParserI
 	return p.bindArgs(c)
 }
 
+func (p *parser) checkUnknownCommand(c *parsedCommand) error {
+	if len(c.args) == 0 || strings.HasPrefix(c.args[0], "-") || len(c.command.Subcommands) == 0 {
+		return nil
+	}
+
+	var names []string
+
+	for _, cmd := range c.command.Subcommands {
+		names = append(names, cmd.Name)
+	}
+
+	if suggestion := getSuggestion(c.args[0], names); suggestion != "" {
+		return p.catalog.Error(MsgSuggestedCommand, c.args[0], suggestion)
+	}
+
+	return p.catalog.Error(MsgUnknownCommand, c.args[0])
+}
+
+func (p *parser) resolveHelpCommand(r *parsedCommand) error {
+	for _, cmd := range p.parsedCommands {
+		if !cmd.command.helpCommand {
+			continue
+		}
+
+		helpCmd, topic, helpErr := resolveHelpPath(r.command, cmd.Operands, p.catalog)
+
+		if helpErr != nil {
+			return helpErr
+		}
+
+		if topic != nil {
+			r.HelpTopic = topic
+
+			return nil
+		}
+
+		p.helpMode = true
+		p.HelpCommand = helpCmd
+	}
+
+	return nil
+}
+
 func (p *parser) bindArgs(c *parsedCommand) error {
 	if len(c.parsedArgs) == 0 {
 		return nil
@@ -147,50 +379,250 @@ func (p *parser) bindArgs(c *parsedCommand) error {
 			c.VersionMode = true
 		}
 
-		if argErr := setArgValue(arg); argErr != nil {
-			return argErr
+		if arg.name == "help-json" && arg.builtin {
+			p.HelpCommand = c.command
+			p.schemaMode = true
+		}
+
+		if isMissingArgValue(arg) && p.prompter.Interactive() {
+			p.promptArgs = append(p.promptArgs, arg)
+
+			continue
+		}
+
+		if bindErr := p.bindArgValue(arg); bindErr != nil {
+			return bindErr
 		}
 	}
 
 	return nil
 }
 
-func newWalker(c *command) *commandWalker {
-	return &commandWalker{
-		root: c,
-		path: c.Subcommands,
+func (p *parser) bindArgValue(a *parsedArg) error {
+	if choiceErr := checkArgChoices(a, p.catalog); choiceErr != nil {
+		return choiceErr
+	}
+
+	if argErr := setArgValue(a, p.catalog); argErr != nil {
+		return argErr
+	}
+
+	if a.name == "output" && a.builtin {
+		p.outputFormat = formatArgValue(a.bindVal)
+		p.outputSet = true
+	}
+
+	if a.name == "color" && a.builtin {
+		p.color = formatArgValue(a.bindVal)
+
+		if p.color == "" {
+			p.color = "always"
+		}
 	}
+
+	return nil
 }
 
-func (w *commandWalker) Walk(a string) *command {
-	for _, cmd := range w.path {
-		if a == cmd.Name {
-			w.updatePath(cmd)
+func (p *parser) promptArgValue(a *parsedArg) error {
+	answer, promptErr := p.prompter.Prompt(&Prompt{
+		Choices:   a.choices,
+		Default:   formatArgValue(a.bindVal),
+		Name:      a.name,
+		Secret:    a.secret,
+		UsageText: translateUsage(p.catalog, a.usageText),
+	})
+
+	if promptErr != nil {
+		return promptErr
+	}
+
+	if answer != "" {
+		a.value = append(a.value, answer)
+	}
+
+	return nil
+}
+
+func (p *parser) bindOperands(c *parsedCommand) error {
+	for i, operand := range c.command.Operands {
+		value := ""
 
-			return cmd
+		switch {
+		case i < len(c.Operands):
+			value = c.Operands[i]
+		case !operand.Required:
+			continue
+		case !p.prompter.Interactive():
+			return p.catalog.Error(MsgMissingOperand, operand.Name)
+		default:
+			answer, promptErr := p.prompter.Prompt(&Prompt{
+				Choices:   operand.Choices,
+				Default:   formatArgValue(operand.Value),
+				Name:      operand.Name,
+				UsageText: translateUsage(p.catalog, operand.UsageText),
+			})
+
+			if promptErr != nil {
+				return promptErr
+			}
+
+			if answer == "" {
+				return p.catalog.Error(MsgMissingOperand, operand.Name)
+			}
+
+			value = answer
+		}
+
+		if !isValidChoice(value, operand.Choices) {
+			return p.catalog.Error(MsgInvalidOperand, value, operand.Name)
+		}
+
+		if operand.Value != nil {
+			*operand.Value = value
+		}
+
+		if operand.File != nil {
+			if fileErr := setFileValue(operand.File, value, p.catalog); fileErr != nil {
+				return fileErr
+			}
 		}
 	}
 
 	return nil
 }
 
-func (w *commandWalker) updatePath(c *command) {
-	walkablePath := append([]*command{}, c.Subcommands...)
-	parent := c.Parent
+func withBuiltinArgConfigs(a []*argConfig) []*argConfig {
+	argConfigs := append([]*argConfig{}, a...)
+	helpJSONArgExists := false
+	outputArgExists := false
+
+	for _, argConfig := range a {
+		if argConfig.Name == "help-json" {
+			helpJSONArgExists = true
+		}
+
+		if argConfig.Name == "output" {
+			outputArgExists = true
+		}
+	}
+
+	if !helpJSONArgExists {
+		val := false
+		argConfigs = append(argConfigs, &argConfig{
+			bind:       bindBoolArg,
+			builtin:    true,
+			Name:       "help-json",
+			Repeatable: true,
+			UsageText:  "display the command schema as JSON",
+			Value:      &val,
+		})
+	}
+
+	if !outputArgExists {
+		val := "text"
+		argConfigs = append(argConfigs, &argConfig{
+			bind:      bindStringArg,
+			builtin:   true,
+			Choices:   []string{"text", "json"},
+			Name:      "output",
+			Required:  true,
+			UsageText: "output format for version information",
+			Value:     &val,
+		})
+	}
+
+	return argConfigs
+}
+
+func getPluginNameIndex(a []string, i *argIndex) int {
+	for index := 0; index < len(a); index++ {
+		arg := a[index]
+
+		if arg == "--" {
+			return -1
+		}
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return index
+		}
+
+		if strings.HasPrefix(arg, "--") {
+			option := strings.SplitN(strings.TrimPrefix(arg, "--"), "=", 2)
+			argConfig := i.find(option[0], 0)
+
+			if argConfig == nil {
+				return -1
+			}
+
+			if argConfig.Required && len(option) == 1 {
+				index++
+			}
+
+			continue
+		}
+
+		options := []rune(strings.TrimPrefix(arg, "-"))
+
+		for j, char := range options {
+			argConfig := i.find(string(char), char)
+
+			if argConfig == nil {
+				return -1
+			}
+
+			if argConfig.Required {
+				if j == len(options)-1 {
+					index++
+				}
+
+				break
+			}
+		}
+	}
+
+	return -1
+}
+
+func getenv(e []string, k string) string {
+	if e == nil {
+		return os.Getenv(k)
+	}
+
+	value := ""
 
-	for parent != nil {
-		walkablePath = append(walkablePath, parent.Subcommands...)
-		parent = parent.Parent
+	for _, entry := range e {
+		if strings.HasPrefix(entry, k+"=") {
+			value = strings.TrimPrefix(entry, k+"=")
+		}
 	}
 
-	w.path = walkablePath
+	return value
+}
+
+func newWalker(c *command) *commandWalker {
+	return &commandWalker{
+		current: c,
+	}
+}
+
+func (w *commandWalker) Walk(a string) *command {
+	for cmd := w.current; cmd != nil; cmd = cmd.Parent {
+		if found := cmd.getSubcommand(a); found != nil {
+			w.current = found
+
+			return found
+		}
+	}
+
+	return nil
 }
 
 func getGnuRules() []argParserRule {
 	return []argParserRule{
-		checkGnuOptionValidity,
 		checkPosixArgsTerminated,
 		checkPosixArgIsOperand,
+		checkArgIsTrailingOperand,
+		checkGnuOptionValidity,
 		checkGnuArgIsLongOption,
 		checkGnuArgIsLongOptionArgument,
 		checkPosixArgIsOption,
@@ -198,9 +630,9 @@ func getGnuRules() []argParserRule {
 	}
 }
 
-func checkGnuOptionValidity(a *string, i int, _ *argParserContext) (bool, error) {
+func checkGnuOptionValidity(a *string, i int, c *argParserContext) (bool, error) {
 	if i == 0 && !strings.HasPrefix(*a, "-") && !strings.HasPrefix(*a, "--") {
-		return false, errors.New("invalid GNU option: " + *a)
+		return false, c.catalog.Error(MsgInvalidGnuOption, *a)
 	}
 
 	return false, nil
@@ -222,62 +654,50 @@ func checkGnuArgIsLongOption(a *string, _ int, c *argParserContext) (bool, error
 	}
 
 	if len(optArgValues) > 1 {
-		return false, errors.New(
-			"invalid GNU option argument: '" + strings.Join(optArgValues[1:], ",") + "' for option: --" + option,
-		)
-	}
+		value := strings.Join(optArgValues[1:], ",")
 
-	for _, argConfig := range c.argConfigs {
-		if option != argConfig.Name {
-			continue
+		if argConfig := c.argIndex.find(option, 0); argConfig != nil && argConfig.Secret {
+			value = SecretMask
 		}
 
+		return false, c.catalog.Error(MsgInvalidGnuOptArg, value, option)
+	}
+
+	if argConfig := c.argIndex.find(option, 0); argConfig != nil {
 		for _, argNamePart := range strings.Split(argConfig.Name, "-") {
 			for _, char := range argNamePart {
 				if !isValidPosixOptionName(string(char), char) {
-					return false, errors.New("invalid GNU option name: --" + option)
+					return false, c.catalog.Error(MsgInvalidGnuOptionName, option)
 				}
 			}
 		}
 
-		for _, pArg := range c.parsedArgs {
-			if option == pArg.name && !argConfig.Repeatable {
-				return false, errors.New("non-repeatable GNU option: --" + option)
-			}
+		if c.parsedNames[option] && !argConfig.Repeatable {
+			return false, c.catalog.Error(MsgNonRepeatableGnuOpt, option)
 		}
 
 		updateArgParserContext(argConfig, option, *a, c)
 		c.lastParsedArg.value = optArgValues
 		argParsed = true
-
-		break
 	}
 
 	return argParsed, nil
 }
 
 func checkGnuArgIsLongOptionArgument(a *string, _ int, c *argParserContext) (bool, error) {
-	if c.lastParsedArg == nil {
+	pArg := c.lastParsedArg
+
+	if pArg == nil || !strings.HasPrefix(pArg.rawArg, "--") {
 		return false, nil
 	}
 
-	for _, pArg := range c.parsedArgs {
-		if c.lastParsedArg != pArg || !strings.HasPrefix(pArg.rawArg, "--") {
-			continue
-		}
-
-		if !pArg.required && len(pArg.value) == 0 {
-			return false, errors.New(
-				"optional GNU option-argument '" + *a + "' must be provided with option '--" + pArg.name + "' separated by '='",
-			)
-		}
-
-		pArg.value = append(pArg.value, *a)
-
-		return true, nil
+	if !pArg.required && len(pArg.value) == 0 {
+		return false, c.catalog.Error(MsgOptionalGnuOptArg, maskArgValue(pArg, *a), pArg.name)
 	}
 
-	return false, nil
+	pArg.value = append(pArg.value, *a)
+
+	return true, nil
 }
 
 func getPosixArgParserContext(a []string) *argParserContext {
@@ -290,23 +710,25 @@ func getPosixArgParserContext(a []string) *argParserContext {
 	}
 
 	return &argParserContext{
+		parsedNames:     map[string]bool{},
 		terminatorIndex: terminatorIndex,
 	}
 }
 
 func getPosixRules() []argParserRule {
 	return []argParserRule{
-		checkPosixOptionValidity,
 		checkPosixArgsTerminated,
 		checkPosixArgIsOperand,
+		checkArgIsTrailingOperand,
+		checkPosixOptionValidity,
 		checkPosixArgIsOption,
 		checkPosixArgIsOptionArgument,
 	}
 }
 
-func checkPosixOptionValidity(a *string, i int, _ *argParserContext) (bool, error) {
+func checkPosixOptionValidity(a *string, i int, c *argParserContext) (bool, error) {
 	if i == 0 && !strings.HasPrefix(*a, "-") {
-		return false, errors.New("invalid POSIX option: " + *a)
+		return false, c.catalog.Error(MsgInvalidPosixOption, *a)
 	}
 
 	return false, nil
@@ -332,6 +754,17 @@ func checkPosixArgIsOperand(a *string, _ int, c *argParserContext) (bool, error)
 	return false, nil
 }
 
+func checkArgIsTrailingOperand(a *string, _ int, c *argParserContext) (bool, error) {
+	if !c.operandsAllowed || (strings.HasPrefix(*a, "-") && *a != "-") || isExpectingOptArg(c.lastParsedArg) {
+		return false, nil
+	}
+
+	c.terminated = true
+	c.operands = append(c.operands, *a)
+
+	return true, nil
+}
+
 func checkPosixArgIsOption(a *string, _ int, c *argParserContext) (bool, error) {
 	argParsed := false
 
@@ -345,325 +778,432 @@ func checkPosixArgIsOption(a *string, _ int, c *argParserContext) (bool, error)
 	for _, char := range option {
 		optName := string(char)
 
-		for _, argConfig := range c.argConfigs {
-			if optName != argConfig.Name && char != argConfig.ShortName {
-				argParsed = false
+		argConfig := c.argIndex.find(optName, char)
 
-				continue
-			}
-
-			argConfig.Required = true
-
-			if !isValidPosixOptionName(argConfig.Name, argConfig.ShortName) {
-				return false, errors.New("invalid POSIX option name: -" + option)
-			}
+		if argConfig == nil {
+			argParsed = false
 
-			for _, pArg := range c.parsedArgs {
-				if option == pArg.name && !argConfig.Repeatable {
-					return false, errors.New("non-repeatable POSIX option: -" + option)
-				}
-			}
+			continue
+		}
 
-			updateArgParserContext(argConfig, optName, *a, c)
-			argParsed = true
-			restOfArgs = strings.TrimPrefix(restOfArgs, optName)
-			*a = strings.TrimPrefix(restOfArgs, optName)
+		if !isValidPosixOptionName(argConfig.Name, argConfig.ShortName) {
+			return false, c.catalog.Error(MsgInvalidPosixOptionName, option)
+		}
 
-			break
+		if c.parsedNames[option] && !argConfig.Repeatable {
+			return false, c.catalog.Error(MsgNonRepeatablePosixOpt, option)
 		}
+
+		updateArgParserContext(argConfig, optName, *a, c)
+		c.lastParsedArg.required = true
+		argParsed = true
+		restOfArgs = strings.TrimPrefix(restOfArgs, optName)
+		*a = strings.TrimPrefix(restOfArgs, optName)
 	}
 
 	return argParsed, nil
 }
 
 func checkPosixArgIsOptionArgument(a *string, _ int, c *argParserContext) (bool, error) {
-	if c.lastParsedArg != nil {
-		for _, pArg := range c.parsedArgs {
-			if c.lastParsedArg == pArg {
-				pArg.value = append(pArg.value, *a)
-
-				return true, nil
-			}
-		}
+	if c.lastParsedArg == nil {
+		return false, nil
 	}
 
-	return false, nil
+	c.lastParsedArg.value = append(c.lastParsedArg.value, *a)
+
+	return true, nil
 }
 
 func updateArgParserContext(a *argConfig, o string, r string, c *argParserContext) {
 	pArg := &parsedArg{
-		bindVal:  a.Value,
-		name:     o,
-		rawArg:   r,
-		required: a.Required,
-		value:    []string{},
+		bind:      a.bind,
+		bindVal:   a.Value,
+		builtin:   a.builtin,
+		choices:   a.Choices,
+		name:      o,
+		rawArg:    r,
+		required:  a.Required,
+		secret:    a.Secret,
+		usageText: a.UsageText,
+		value:     []string{},
 	}
 	c.lastParsedArg = pArg
 	c.parsedArgs = append(c.parsedArgs, pArg)
+	c.parsedNames[o] = true
+}
+
+func isExpectingOptArg(a *parsedArg) bool {
+	if a == nil || !a.required || len(a.value) > 0 {
+		return false
+	}
+
+	_, isBool := a.bindVal.(*bool)
+
+	return !isBool
 }
 
-func isValidPosixListArg(a *parsedArg) error {
+func isMissingArgValue(a *parsedArg) bool {
+	switch a.bindVal.(type) {
+	case *bool:
+		return false
+	case *[]float64, *[]int, *[]int64, *[]string, *[]uint, *[]uint64:
+		return len(a.value) == 0
+	default:
+		return a.required && len(a.value) == 0
+	}
+}
+
+func checkArgChoices(a *parsedArg, m Catalog) error {
+	if len(a.choices) == 0 {
+		return nil
+	}
+
+	for _, argVal := range a.value {
+		vals := []string{argVal}
+
+		switch a.bindVal.(type) {
+		case *[]float64, *[]int, *[]int64, *[]string, *[]uint, *[]uint64:
+			vals = strings.Split(argVal, ",")
+		}
+
+		for _, val := range vals {
+			if !isValidChoice(strings.TrimSpace(val), a.choices) {
+				return m.Error(MsgInvalidOptArg, maskArgValue(a, val), a.name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isValidPosixListArg(a *parsedArg, m Catalog) error {
 	if len(a.value) == 0 {
-		return errors.New("no POSIX option-arguments provided for option: -" + a.name)
+		return m.Error(MsgMissingPosixOptArgs, formatOptionName(a))
 	}
 
 	return nil
 }
 
-func isValidPosixNonlistArg(a *parsedArg) error {
+func isValidPosixNonlistArg(a *parsedArg, m Catalog) error {
 	if a.required && len(a.value) == 0 {
-		return errors.New("missing option-argument for required option: " + a.name)
+		return m.Error(MsgMissingOptArg, a.name)
 	}
 
 	if a.required && len(a.value) > 1 {
-		return errors.New("invalid POSIX option-argument: '" + strings.Join(a.value, ",") +
-			"' for option: -" + a.name,
-		)
+		return m.Error(MsgInvalidPosixOptArg, maskArgValue(a, strings.Join(a.value, ",")), formatOptionName(a))
 	}
 
 	return nil
 }
 
+func formatOptionName(a *parsedArg) string {
+	if strings.HasPrefix(a.rawArg, "--") {
+		return "--" + a.name
+	}
+
+	return "-" + a.name
+}
+
 func isValidPosixOptionName(s string, r rune) bool {
 	return (s != "" && len(s) == 1 && ((s[0] >= 'a' && s[0] <= 'z') || (s[0] >= 'A' && s[0] <= 'Z')) && s[0] != 'W') ||
 		(((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')) && r != 'W')
 }
 
-func setArgValue(p *parsedArg) error {
-	switch p.bindVal.(type) {
-	case *bool:
-		if len(p.value) > 0 && p.value[0] != "" {
-			return errors.New(
-				"invalid option-argument: '" + strings.Join(p.value, ",") +
-					"' for option: " + p.name,
-			)
-		}
+func setArgValue(p *parsedArg, m Catalog) error {
+	if p.bind == nil {
+		return m.Error(MsgInvalidOption, p.name)
+	}
 
-		*(p.bindVal.(*bool)) = true
-	case *float64:
-		if err := isValidPosixNonlistArg(p); err != nil {
-			return err
-		}
+	return p.bind(p, m)
+}
 
-		if len(p.value) == 0 {
-			return nil
-		}
+func bindBoolArg(p *parsedArg, m Catalog) error {
+	if len(p.value) > 0 && p.value[0] != "" {
+		return m.Error(MsgInvalidOptArg, maskArgValue(p, strings.Join(p.value, ",")), p.name)
+	}
 
-		argVal := p.value[0]
-		float64Val, float64Err := strconv.ParseFloat(argVal, 64)
+	*(p.bindVal.(*bool)) = true
 
-		if float64Err != nil || argVal == "" {
-			return errors.New("invalid option-argument: '" + argVal + "' for option: " + p.name)
-		}
+	return nil
+}
 
-		*(p.bindVal.(*float64)) = float64Val
-	case *[]float64:
-		if listArgErr := isValidPosixListArg(p); listArgErr != nil {
-			return listArgErr
-		}
+func bindFloat64Arg(p *parsedArg, m Catalog) error {
+	if err := isValidPosixNonlistArg(p, m); err != nil {
+		return err
+	}
 
-		var float64Vals []float64
+	if len(p.value) == 0 {
+		return nil
+	}
 
-		for _, argVal := range p.value {
-			csv := strings.Split(argVal, ",")
+	argVal := p.value[0]
+	float64Val, float64Err := strconv.ParseFloat(argVal, 64)
 
-			for _, val := range csv {
-				float64Val, float64Err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+	if float64Err != nil || argVal == "" {
+		return m.Error(MsgInvalidOptArg, maskArgValue(p, argVal), p.name)
+	}
 
-				if float64Err != nil || val == "" {
-					return errors.New("invalid option-argument: '" + val + "' for option: " + p.name)
-				}
+	*(p.bindVal.(*float64)) = float64Val
 
-				float64Vals = append(float64Vals, float64Val)
+	return nil
+}
+
+func bindFloat64ListArg(p *parsedArg, m Catalog) error {
+	if listArgErr := isValidPosixListArg(p, m); listArgErr != nil {
+		return listArgErr
+	}
+
+	var float64Vals []float64
+
+	for _, argVal := range p.value {
+		csv := strings.Split(argVal, ",")
+
+		for _, val := range csv {
+			float64Val, float64Err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+
+			if float64Err != nil || val == "" {
+				return m.Error(MsgInvalidOptArg, maskArgValue(p, val), p.name)
 			}
-		}
 
-		*(p.bindVal.(*[]float64)) = float64Vals
-	case *int:
-		if err := isValidPosixNonlistArg(p); err != nil {
-			return err
+			float64Vals = append(float64Vals, float64Val)
 		}
+	}
 
-		if len(p.value) == 0 {
-			return nil
-		}
+	*(p.bindVal.(*[]float64)) = float64Vals
+
+	return nil
+}
 
-		argVal := p.value[0]
-		intVal, intErr := strconv.Atoi(argVal)
+func bindIntArg(p *parsedArg, m Catalog) error {
+	if err := isValidPosixNonlistArg(p, m); err != nil {
+		return err
+	}
 
-		if intErr != nil || argVal == "" {
-			return errors.New("invalid option-argument: '" + argVal + "' for option: " + p.name)
-		}
+	if len(p.value) == 0 {
+		return nil
+	}
 
-		*(p.bindVal.(*int)) = intVal
-	case *[]int:
-		if listArgErr := isValidPosixListArg(p); listArgErr != nil {
-			return listArgErr
-		}
+	argVal := p.value[0]
+	intVal, intErr := strconv.Atoi(argVal)
 
-		var intVals []int
+	if intErr != nil || argVal == "" {
+		return m.Error(MsgInvalidOptArg, maskArgValue(p, argVal), p.name)
+	}
+
+	*(p.bindVal.(*int)) = intVal
 
-		for _, argVal := range p.value {
-			csv := strings.Split(argVal, ",")
+	return nil
+}
 
-			for _, val := range csv {
-				intVal, intErr := strconv.Atoi(strings.TrimSpace(val))
+func bindIntListArg(p *parsedArg, m Catalog) error {
+	if listArgErr := isValidPosixListArg(p, m); listArgErr != nil {
+		return listArgErr
+	}
 
-				if intErr != nil || val == "" {
-					return errors.New("invalid option-argument: '" + argVal + "' for option: " + p.name)
-				}
+	var intVals []int
 
-				intVals = append(intVals, intVal)
+	for _, argVal := range p.value {
+		csv := strings.Split(argVal, ",")
+
+		for _, val := range csv {
+			intVal, intErr := strconv.Atoi(strings.TrimSpace(val))
+
+			if intErr != nil || val == "" {
+				return m.Error(MsgInvalidOptArg, maskArgValue(p, argVal), p.name)
 			}
-		}
 
-		*(p.bindVal.(*[]int)) = intVals
-	case *int64:
-		if err := isValidPosixNonlistArg(p); err != nil {
-			return err
+			intVals = append(intVals, intVal)
 		}
+	}
 
-		if len(p.value) == 0 {
-			return nil
-		}
+	*(p.bindVal.(*[]int)) = intVals
 
-		argVal := p.value[0]
-		int64Val, int64Err := strconv.ParseInt(argVal, 10, 64)
+	return nil
+}
 
-		if int64Err != nil || argVal == "" {
-			return errors.New("invalid option-argument: '" + argVal + "' for option: " + p.name)
-		}
+func bindInt64Arg(p *parsedArg, m Catalog) error {
+	if err := isValidPosixNonlistArg(p, m); err != nil {
+		return err
+	}
 
-		*(p.bindVal.(*int64)) = int64Val
-	case *[]int64:
-		if listArgErr := isValidPosixListArg(p); listArgErr != nil {
-			return listArgErr
-		}
+	if len(p.value) == 0 {
+		return nil
+	}
 
-		var int64Vals []int64
+	argVal := p.value[0]
+	int64Val, int64Err := strconv.ParseInt(argVal, 10, 64)
 
-		for _, argVal := range p.value {
-			csv := strings.Split(argVal, ",")
+	if int64Err != nil || argVal == "" {
+		return m.Error(MsgInvalidOptArg, maskArgValue(p, argVal), p.name)
+	}
 
-			for _, val := range csv {
-				int64Val, int64Err := strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+	*(p.bindVal.(*int64)) = int64Val
 
-				if int64Err != nil || val == "" {
-					return errors.New("invalid option-argument: '" + val + "' for option: " + p.name)
-				}
+	return nil
+}
+
+func bindInt64ListArg(p *parsedArg, m Catalog) error {
+	if listArgErr := isValidPosixListArg(p, m); listArgErr != nil {
+		return listArgErr
+	}
+
+	var int64Vals []int64
 
-				int64Vals = append(int64Vals, int64Val)
+	for _, argVal := range p.value {
+		csv := strings.Split(argVal, ",")
+
+		for _, val := range csv {
+			int64Val, int64Err := strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+
+			if int64Err != nil || val == "" {
+				return m.Error(MsgInvalidOptArg, maskArgValue(p, val), p.name)
 			}
-		}
 
-		*(p.bindVal.(*[]int64)) = int64Vals
-	case *string:
-		if err := isValidPosixNonlistArg(p); err != nil {
-			return err
+			int64Vals = append(int64Vals, int64Val)
 		}
+	}
 
-		if len(p.value) == 0 {
-			return nil
-		}
+	*(p.bindVal.(*[]int64)) = int64Vals
 
-		*(p.bindVal.(*string)) = p.value[0]
-	case *[]string:
-		if listArgErr := isValidPosixListArg(p); listArgErr != nil {
-			return listArgErr
-		}
+	return nil
+}
 
-		var stringVals []string
+func bindStringArg(p *parsedArg, m Catalog) error {
+	if err := isValidPosixNonlistArg(p, m); err != nil {
+		return err
+	}
 
-		for _, argVal := range p.value {
-			csv := strings.Split(argVal, ",")
+	if len(p.value) == 0 {
+		return nil
+	}
 
-			for _, val := range csv {
-				stringVals = append(stringVals, val)
-			}
-		}
+	*(p.bindVal.(*string)) = p.value[0]
 
-		*(p.bindVal.(*[]string)) = stringVals
-	case *uint:
-		if err := isValidPosixNonlistArg(p); err != nil {
-			return err
-		}
+	return nil
+}
 
-		if len(p.value) == 0 {
-			return nil
-		}
+func bindStringListArg(p *parsedArg, m Catalog) error {
+	if listArgErr := isValidPosixListArg(p, m); listArgErr != nil {
+		return listArgErr
+	}
 
-		argVal := p.value[0]
-		uintVal, uintErr := strconv.ParseUint(argVal, 10, 0)
+	var stringVals []string
 
-		if uintErr != nil || argVal == "" {
-			return errors.New("invalid option-argument: '" + argVal + "' for option: " + p.name)
-		}
+	for _, argVal := range p.value {
+		csv := strings.Split(argVal, ",")
 
-		*(p.bindVal.(*uint)) = uint(uintVal)
-	case *[]uint:
-		if listArgErr := isValidPosixListArg(p); listArgErr != nil {
-			return listArgErr
+		for _, val := range csv {
+			stringVals = append(stringVals, val)
 		}
+	}
 
-		var uintVals []uint
+	*(p.bindVal.(*[]string)) = stringVals
 
-		for _, argVal := range p.value {
-			csv := strings.Split(argVal, ",")
+	return nil
+}
 
-			for _, val := range csv {
-				uintVal, uintErr := strconv.ParseUint(strings.TrimSpace(val), 10, 0)
+func bindUintArg(p *parsedArg, m Catalog) error {
+	if err := isValidPosixNonlistArg(p, m); err != nil {
+		return err
+	}
 
-				if uintErr != nil || val == "" {
-					return errors.New("invalid option-argument: '" + val + "' for option: " + p.name)
-				}
+	if len(p.value) == 0 {
+		return nil
+	}
+
+	argVal := p.value[0]
+	uintVal, uintErr := strconv.ParseUint(argVal, 10, 0)
+
+	if uintErr != nil || argVal == "" {
+		return m.Error(MsgInvalidOptArg, maskArgValue(p, argVal), p.name)
+	}
+
+	*(p.bindVal.(*uint)) = uint(uintVal)
 
-				uintVals = append(uintVals, uint(uintVal))
+	return nil
+}
+
+func bindUintListArg(p *parsedArg, m Catalog) error {
+	if listArgErr := isValidPosixListArg(p, m); listArgErr != nil {
+		return listArgErr
+	}
+
+	var uintVals []uint
+
+	for _, argVal := range p.value {
+		csv := strings.Split(argVal, ",")
+
+		for _, val := range csv {
+			uintVal, uintErr := strconv.ParseUint(strings.TrimSpace(val), 10, 0)
+
+			if uintErr != nil || val == "" {
+				return m.Error(MsgInvalidOptArg, maskArgValue(p, val), p.name)
 			}
-		}
 
-		*(p.bindVal.(*[]uint)) = uintVals
-	case *uint64:
-		if err := isValidPosixNonlistArg(p); err != nil {
-			return err
+			uintVals = append(uintVals, uint(uintVal))
 		}
+	}
 
-		if len(p.value) == 0 {
-			return nil
-		}
+	*(p.bindVal.(*[]uint)) = uintVals
+
+	return nil
+}
 
-		argVal := p.value[0]
-		uint64Val, uint64Err := strconv.ParseUint(argVal, 10, 64)
+func bindUint64Arg(p *parsedArg, m Catalog) error {
+	if err := isValidPosixNonlistArg(p, m); err != nil {
+		return err
+	}
 
-		if uint64Err != nil || argVal == "" {
-			return errors.New("invalid option-argument: '" + argVal + "' for option: " + p.name)
-		}
+	if len(p.value) == 0 {
+		return nil
+	}
 
-		*(p.bindVal.(*uint64)) = uint64Val
-	case *[]uint64:
-		if listArgErr := isValidPosixListArg(p); listArgErr != nil {
-			return listArgErr
-		}
+	argVal := p.value[0]
+	uint64Val, uint64Err := strconv.ParseUint(argVal, 10, 64)
 
-		var uint64Vals []uint64
+	if uint64Err != nil || argVal == "" {
+		return m.Error(MsgInvalidOptArg, maskArgValue(p, argVal), p.name)
+	}
+
+	*(p.bindVal.(*uint64)) = uint64Val
 
-		for _, argVal := range p.value {
-			csv := strings.Split(argVal, ",")
+	return nil
+}
 
-			for _, val := range csv {
-				uint64Val, uint64Err := strconv.ParseUint(strings.TrimSpace(val), 10, 64)
+func bindUint64ListArg(p *parsedArg, m Catalog) error {
+	if listArgErr := isValidPosixListArg(p, m); listArgErr != nil {
+		return listArgErr
+	}
 
-				if uint64Err != nil || val == "" {
-					return errors.New("invalid option-argument: '" + val + "' for option: " + p.name)
-				}
+	var uint64Vals []uint64
 
-				uint64Vals = append(uint64Vals, uint64Val)
+	for _, argVal := range p.value {
+		csv := strings.Split(argVal, ",")
+
+		for _, val := range csv {
+			uint64Val, uint64Err := strconv.ParseUint(strings.TrimSpace(val), 10, 64)
+
+			if uint64Err != nil || val == "" {
+				return m.Error(MsgInvalidOptArg, maskArgValue(p, val), p.name)
 			}
-		}
 
-		*(p.bindVal.(*[]uint64)) = uint64Vals
-	default:
-		return errors.New("invalid option: " + p.name)
+			uint64Vals = append(uint64Vals, uint64Val)
+		}
 	}
 
+	*(p.bindVal.(*[]uint64)) = uint64Vals
+
 	return nil
 }
+
+func bindFileArg(p *parsedArg, m Catalog) error {
+	if err := isValidPosixNonlistArg(p, m); err != nil {
+		return err
+	}
+
+	if len(p.value) == 0 {
+		return nil
+	}
+
+	return setFileValue(p.bindVal.(*File), p.value[0], m)
+}
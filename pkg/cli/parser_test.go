@@ -42,9 +42,33 @@ func getParserTestCases() map[string]func(t *testing.T, n string) {
 		"should error when required uint64 list opt has no opt-arg":  shouldErrorWhenRequiredUint64ListOptHasNoOptArg,
 		"should parse when operands provided correctly":              shouldParseWhenOperandsProvidedCorrectly,
 		"should parse when args provided correctly":                  shouldParseWhenPosixArgsProvidedCorrectly,
+		"should prompt for missing opt-arg when interactive":         shouldPromptForMissingRequiredOptArgWhenInteractive,
+		"should error when opt-arg is not a valid choice":            shouldErrorWhenOptArgIsNotAValidChoice,
+		"should bind operands to definitions":                        shouldBindOperandsToDefinitions,
+		"should error when required operand missing":                 shouldErrorWhenRequiredOperandMissing,
+		"should prompt for required operand when interactive":        shouldPromptForRequiredOperandWhenInteractive,
+		"should not prompt in help mode":                             shouldNotPromptInHelpMode,
+		"should bind operands after options":                         shouldBindOperandsAfterOptions,
+		"should bind operands without options":                       shouldBindOperandsWithoutOptions,
+		"should name GNU long option in option-argument error":       shouldNameGnuLongOptionInOptArgError,
 	}
 }
 
+type testPrompter struct {
+	answer  string
+	prompts []*cli.Prompt
+}
+
+func (p *testPrompter) Interactive() bool {
+	return true
+}
+
+func (p *testPrompter) Prompt(q *cli.Prompt) (string, error) {
+	p.prompts = append(p.prompts, q)
+
+	return p.answer, nil
+}
+
 func shouldErrorWhenUnsupportedParseSyntaxUsed(t *testing.T, n string) {
 	os.Args = []string{"testcmd"}
 	cmd := cli.NewCommand("testcmd", context.Background())
@@ -1135,3 +1159,279 @@ func shouldParseWhenPosixArgsProvidedCorrectly(t *testing.T, n string) {
 		}
 	}
 }
+
+func shouldPromptForMissingRequiredOptArgWhenInteractive(t *testing.T, n string) {
+	testCases := map[string]cli.ArgSyntax{
+		"GNU":   cli.GNU,
+		"POSIX": cli.POSIX,
+	}
+
+	for syntaxName, syntax := range testCases {
+		os.Args = []string{"testcmd", "-a"}
+		cmd := cli.NewCommand("testcmd", context.Background())
+		val := "default"
+		cmd.AddStringArg(&val, &cli.ArgDefinition{Name: "a", ShortName: 'a', Secret: true})
+		prompter := &testPrompter{answer: "prompted"}
+		parser := cli.NewParser(syntax, cmd)
+		parser.SetPrompter(prompter)
+		_, err := parser.Parse()
+
+		if err != nil || val != "prompted" || len(prompter.prompts) != 1 {
+			t.Fail()
+			t.Log(n + ": did not prompt for missing " + syntaxName + " option-argument")
+
+			continue
+		}
+
+		if prompt := prompter.prompts[0]; prompt.Default != "default" || !prompt.Secret {
+			t.Fail()
+			t.Log(n + ": did not configure " + syntaxName + " prompt from option definition")
+		}
+	}
+}
+
+func shouldErrorWhenOptArgIsNotAValidChoice(t *testing.T, n string) {
+	testCases := map[string]bool{
+		"--color=red":  false,
+		"--color=blue": true,
+	}
+
+	for arg, valid := range testCases {
+		os.Args = []string{"testcmd", arg}
+		cmd := cli.NewCommand("testcmd", context.Background())
+		val := ""
+		cmd.AddStringArg(&val, &cli.ArgDefinition{Name: "color", Choices: []string{"blue", "green"}})
+		_, err := cli.NewParser(cli.GNU, cmd).Parse()
+
+		if (err == nil) != valid {
+			t.Fail()
+			t.Log(n + ": incorrectly validated choice for " + arg)
+		}
+	}
+}
+
+func shouldBindOperandsToDefinitions(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "--", "foo", "bar"}
+	cmd := cli.NewCommand("testcmd", context.Background())
+	first := ""
+	second := ""
+	cmd.AddOperand(&first, &cli.OperandDefinition{Name: "first", Required: true})
+	cmd.AddOperand(&second, &cli.OperandDefinition{Name: "second"})
+	_, err := cli.NewParser(cli.GNU, cmd).Parse()
+
+	if err != nil || first != "foo" || second != "bar" {
+		t.Fail()
+		t.Log(n + ": failed to bind operands")
+	}
+}
+
+func shouldErrorWhenRequiredOperandMissing(t *testing.T, n string) {
+	os.Args = []string{"testcmd"}
+	cmd := cli.NewCommand("testcmd", context.Background())
+	val := ""
+	cmd.AddOperand(&val, &cli.OperandDefinition{Name: "title", Required: true})
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.SetPrompter(cli.NewPrompter(strings.NewReader("title\n"), &strings.Builder{}))
+	_, err := parser.Parse()
+
+	if err == nil {
+		t.Fail()
+		t.Log(n + ": did not error on missing required operand without terminal")
+	}
+}
+
+func shouldPromptForRequiredOperandWhenInteractive(t *testing.T, n string) {
+	os.Args = []string{"testcmd"}
+	cmd := cli.NewCommand("testcmd", context.Background())
+	val := ""
+	cmd.AddOperand(&val, &cli.OperandDefinition{Name: "title", Required: true})
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.SetPrompter(&testPrompter{answer: "My Page"})
+	_, err := parser.Parse()
+
+	if err != nil || val != "My Page" {
+		t.Fail()
+		t.Log(n + ": did not prompt for missing required operand")
+	}
+}
+
+func shouldNotPromptInHelpMode(t *testing.T, n string) {
+	testCases := map[string][]string{
+		"help after option":     {"testcmd", "-a", "--help"},
+		"help before option":    {"testcmd", "-h", "-a"},
+		"help subcommand":       {"testcmd", "-a", "help", "sub"},
+		"subcommand help flag":  {"testcmd", "-a", "sub", "--help"},
+		"schema after option":   {"testcmd", "-a", "--help-json"},
+		"version after options": {"testcmd", "-a", "--version"},
+	}
+
+	for name, args := range testCases {
+		os.Args = args
+		cmd := cli.NewCommand("testcmd", context.Background())
+		val := ""
+		title := ""
+		cmd.AddStringArg(&val, &cli.ArgDefinition{Name: "aaa", ShortName: 'a', Required: true})
+		cmd.AddOperand(&title, &cli.OperandDefinition{Name: "title", Required: true})
+		cmd.AddSubcommand(cli.NewCommand("sub", context.Background()))
+		prompter := &testPrompter{answer: "prompted"}
+		parser := cli.NewParser(cli.GNU, cmd)
+		parser.SetPrompter(prompter)
+		_, err := parser.Parse()
+
+		if err != nil || len(prompter.prompts) != 0 {
+			t.Fail()
+			t.Logf("%s: %s: prompted %d times (%v)", n, name, len(prompter.prompts), err)
+		}
+	}
+}
+
+func shouldBindOperandsAfterOptions(t *testing.T, n string) {
+	testCases := map[string]struct {
+		syntax cli.ArgSyntax
+		args   []string
+	}{
+		"GNU option with '='":        {cli.GNU, []string{"testcmd", "--name=foo", "mysite"}},
+		"GNU option and argument":    {cli.GNU, []string{"testcmd", "--name", "foo", "mysite"}},
+		"GNU bool and short option":  {cli.GNU, []string{"testcmd", "-f", "-n", "foo", "mysite"}},
+		"POSIX option and argument":  {cli.POSIX, []string{"testcmd", "-fn", "foo", "mysite"}},
+		"POSIX bool before operand":  {cli.POSIX, []string{"testcmd", "-n", "foo", "-f", "mysite"}},
+		"operand then terminator":    {cli.GNU, []string{"testcmd", "--name=foo", "mysite", "--", "--force"}},
+		"stdin operand after option": {cli.POSIX, []string{"testcmd", "-n", "foo", "-"}},
+	}
+
+	for name, test := range testCases {
+		os.Args = test.args
+		cmd := cli.NewCommand("testcmd", context.Background())
+		force := false
+		siteName := ""
+		dir := ""
+		cmd.AddBoolArg(&force, &cli.ArgDefinition{Name: "force", ShortName: 'f'})
+		cmd.AddStringArg(&siteName, &cli.ArgDefinition{Name: "name", Required: true, ShortName: 'n'})
+		cmd.AddOperand(&dir, &cli.OperandDefinition{Name: "dir", Required: true})
+		parsedCommands, err := cli.NewParser(test.syntax, cmd).Parse()
+		expected := test.args[len(test.args)-1]
+
+		if name == "operand then terminator" {
+			expected = "mysite"
+		}
+
+		if err != nil || siteName != "foo" || dir != expected || len(parsedCommands[0].Operands) == 0 {
+			t.Fail()
+			t.Logf("%s: %s: bound name '%s' and dir '%s' (%v)", n, name, siteName, dir, err)
+		}
+	}
+}
+
+func shouldBindOperandsWithoutOptions(t *testing.T, n string) {
+	for syntaxName, syntax := range map[string]cli.ArgSyntax{"GNU": cli.GNU, "POSIX": cli.POSIX} {
+		os.Args = []string{"testcmd", "mysite", "extra"}
+		cmd := cli.NewCommand("testcmd", context.Background())
+		dir := ""
+		cmd.AddOperand(&dir, &cli.OperandDefinition{Name: "dir", Required: true})
+		parsedCommands, err := cli.NewParser(syntax, cmd).Parse()
+
+		if err != nil || dir != "mysite" || strings.Join(parsedCommands[0].Operands, " ") != "mysite extra" {
+			t.Fail()
+			t.Log(n + ": did not bind " + syntaxName + " operand without options")
+		}
+	}
+}
+
+func shouldNameGnuLongOptionInOptArgError(t *testing.T, n string) {
+	testCases := map[string][]string{
+		"--name": {"testcmd", "--name", "foo", "bar"},
+		"-n":     {"testcmd", "-n", "foo", "bar"},
+	}
+
+	for option, args := range testCases {
+		os.Args = args
+		cmd := cli.NewCommand("testcmd", context.Background())
+		siteName := ""
+		cmd.AddStringArg(&siteName, &cli.ArgDefinition{Name: "name", Required: true, ShortName: 'n'})
+		_, err := cli.NewParser(cli.GNU, cmd).Parse()
+
+		if err == nil || !strings.HasSuffix(err.Error(), "for option: "+option) {
+			t.Fail()
+			t.Logf("%s: got error %v for %s", n, err, option)
+		}
+	}
+}
+
+func BenchmarkParser_ParseLongOptions(b *testing.B) {
+	args := []string{"--opt-" + getBenchmarkName(0) + "=x"}
+
+	for i := 1; i < 200; i += 2 {
+		args = append(args, "--opt-"+getBenchmarkName(i), "value")
+	}
+
+	benchmarkParse(b, cli.GNU, newBenchmarkCommand(400, 0, 0), args)
+}
+
+func BenchmarkParser_ParseShortOptions(b *testing.B) {
+	var args []string
+
+	for i := 0; i < 100; i++ {
+		args = append(args, "-abcdefg")
+	}
+
+	benchmarkParse(b, cli.POSIX, newBenchmarkCommand(400, 0, 0), args)
+}
+
+func BenchmarkParser_ParseCommandTree(b *testing.B) {
+	last := "--opt-" + getBenchmarkName(19)
+	args := []string{"cmd-7", last + "=x", "cmd-7", "cmd-7", "--opt-a=x", last + "=y"}
+
+	benchmarkParse(b, cli.GNU, newBenchmarkCommand(400, 8, 3), args)
+}
+
+func benchmarkParse(b *testing.B, s cli.ArgSyntax, c cli.CommandBuilder, a []string) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		parser := cli.NewParser(s, c)
+		parser.SetArgs(a)
+		parser.SetEnv([]string{})
+
+		if _, parseErr := parser.Parse(); parseErr != nil {
+			b.Fatal(parseErr)
+		}
+	}
+}
+
+func newBenchmarkCommand(o int, w int, d int) cli.CommandBuilder {
+	return newBenchmarkSubcommand("benchcmd", o, w, d)
+}
+
+func newBenchmarkSubcommand(n string, o int, w int, d int) cli.CommandBuilder {
+	cmd := cli.NewCommand(n, context.Background())
+
+	for _, char := range "abcdefg" {
+		val := false
+		cmd.AddBoolArg(&val, &cli.ArgDefinition{ShortName: char, Repeatable: true})
+	}
+
+	for i := 0; i < o; i++ {
+		val := ""
+		cmd.AddStringArg(&val, &cli.ArgDefinition{Name: "opt-" + getBenchmarkName(i), Repeatable: true, Required: true})
+	}
+
+	if d == 0 {
+		return cmd
+	}
+
+	for i := 0; i < w; i++ {
+		cmd.AddSubcommand(newBenchmarkSubcommand("cmd-"+strconv.Itoa(i), 20, w, d-1))
+	}
+
+	return cmd
+}
+
+func getBenchmarkName(i int) string {
+	name := string(rune('a' + i%26))
+
+	for i /= 26; i > 0; i /= 26 {
+		name += string(rune('a' + i%26))
+	}
+
+	return name
+}
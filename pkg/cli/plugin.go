@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const signalExitCode = 128
+
+func (e *ExitError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return "exit status " + strconv.Itoa(e.Code)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+func findPlugin(p string, n string, d []string) (string, bool) {
+	if n == "" || strings.ContainsAny(n, `/\`) {
+		return "", false
+	}
+
+	for _, dir := range d {
+		pluginPath := filepath.Join(dir, p+n)
+
+		if isExecutableFile(pluginPath) {
+			return pluginPath, true
+		}
+	}
+
+	return "", false
+}
+
+func listPlugins(p string, d []string) []string {
+	var plugins []string
+	found := map[string]bool{}
+
+	for _, dir := range d {
+		files, readErr := ioutil.ReadDir(dir)
+
+		if readErr != nil {
+			continue
+		}
+
+		for _, file := range files {
+			name := strings.TrimPrefix(file.Name(), p)
+
+			if name == file.Name() || name == "" || found[name] || !isExecutableFile(filepath.Join(dir, file.Name())) {
+				continue
+			}
+
+			found[name] = true
+			plugins = append(plugins, name)
+		}
+	}
+
+	sort.Strings(plugins)
+
+	return plugins
+}
+
+func isExecutableFile(p string) bool {
+	info, statErr := os.Stat(p)
+
+	return statErr == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+func runPlugin(p *plugin, r io.Reader, w io.Writer, e io.Writer) error {
+	cmd := exec.Command(p.Path, p.Args...)
+	cmd.Env = p.Env
+	cmd.Stdin = r
+	cmd.Stdout = w
+	cmd.Stderr = e
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	defer func() {
+		signal.Stop(sigChan)
+		close(sigChan)
+	}()
+
+	if startErr := cmd.Start(); startErr != nil {
+		return startErr
+	}
+
+	go func() {
+		for sig := range sigChan {
+			if sig == syscall.SIGTERM {
+				_ = cmd.Process.Signal(sig)
+			}
+		}
+	}()
+
+	waitErr := cmd.Wait()
+
+	if exitErr, ok := waitErr.(*exec.ExitError); ok {
+		return &ExitError{Code: getPluginExitCode(exitErr)}
+	}
+
+	return waitErr
+}
+
+func getPluginExitCode(e *exec.ExitError) int {
+	if status, ok := e.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return signalExitCode + int(status.Signal())
+	}
+
+	if code := e.ExitCode(); code >= 0 {
+		return code
+	}
+
+	return 1
+}
@@ -0,0 +1,214 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"github.com/sebuckler/teel/pkg/cli"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunner_RunPlugin(t *testing.T) {
+	for name, test := range getPluginTestCases() {
+		test(t, name)
+	}
+}
+
+func getPluginTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should run plugin with remaining args":         shouldRunPluginWithRemainingArgs,
+		"should return plugin exit code":                shouldReturnPluginExitCode,
+		"should map plugin signal to exit code":         shouldMapPluginSignalToExitCode,
+		"should forward terminate signal to plugin":     shouldForwardTerminateSignalToPlugin,
+		"should not forward interrupt to plugin":        shouldNotForwardInterruptToPlugin,
+		"should find plugin on PATH":                    shouldFindPluginOnPath,
+		"should resolve plugin dirs after root options": shouldResolvePluginDirsAfterRootOptions,
+		"should prefer built-in subcommand over plugin": shouldPreferBuiltinSubcommandOverPlugin,
+		"should not run plugin when plugins disabled":   shouldNotRunPluginWhenPluginsDisabled,
+		"should list plugins in root help":              shouldListPluginsInRootHelp,
+	}
+}
+
+func createTestPlugin(t *testing.T, n string, s string) string {
+	dir, dirErr := ioutil.TempDir("", "cli-plugins")
+
+	if dirErr != nil {
+		t.Fatal(dirErr)
+	}
+
+	script := "#!/bin/sh\n" + s + "\n"
+
+	if writeErr := ioutil.WriteFile(filepath.Join(dir, n), []byte(script), 0755); writeErr != nil {
+		t.Fatal(writeErr)
+	}
+
+	return dir
+}
+
+func shouldRunPluginWithRemainingArgs(t *testing.T, n string) {
+	dir := createTestPlugin(t, "testcmd-hello", `echo "hello $@"`)
+	defer os.RemoveAll(dir)
+	os.Args = []string{"testcmd", "hello", "--name", "world"}
+	var strBuilder strings.Builder
+	parser := cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background()))
+	parser.EnablePlugins(dir)
+	runErr := cli.NewRunner(parser, "v1", &strBuilder).Run()
+
+	if runErr != nil || strBuilder.String() != "hello --name world\n" {
+		t.Fail()
+		t.Log(n + ": failed to run plugin")
+	}
+}
+
+func shouldReturnPluginExitCode(t *testing.T, n string) {
+	dir := createTestPlugin(t, "testcmd-fail", "exit 3")
+	defer os.RemoveAll(dir)
+	os.Args = []string{"testcmd", "fail"}
+	parser := cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background()))
+	parser.EnablePlugins(dir)
+	runErr := cli.NewRunner(parser, "v1", &strings.Builder{}).Run()
+	var exitErr *cli.ExitError
+
+	if !errors.As(runErr, &exitErr) || exitErr.Code != 3 {
+		t.Fail()
+		t.Log(n + ": did not return plugin exit code")
+	}
+}
+
+func shouldMapPluginSignalToExitCode(t *testing.T, n string) {
+	dir := createTestPlugin(t, "testcmd-killed", "kill -TERM $$")
+	defer os.RemoveAll(dir)
+	os.Args = []string{"testcmd", "killed"}
+	parser := cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background()))
+	parser.EnablePlugins(dir)
+	runErr := cli.NewRunner(parser, "v1", &strings.Builder{}).Run()
+	var exitErr *cli.ExitError
+
+	if !errors.As(runErr, &exitErr) || exitErr.Code != 143 {
+		t.Fail()
+		t.Log(n + ": did not map plugin signal to exit code")
+	}
+}
+
+func shouldForwardTerminateSignalToPlugin(t *testing.T, n string) {
+	dir := createTestPlugin(t, "testcmd-term", "trap 'exit 3' TERM\nkill -TERM $PPID\nsleep 5 >/dev/null 2>&1 &\nwait $!")
+	defer os.RemoveAll(dir)
+	os.Args = []string{"testcmd", "term"}
+	parser := cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background()))
+	parser.EnablePlugins(dir)
+	runErr := cli.NewRunner(parser, "v1", &strings.Builder{}).Run()
+	var exitErr *cli.ExitError
+
+	if !errors.As(runErr, &exitErr) || exitErr.Code != 3 {
+		t.Fail()
+		t.Logf("%s: plugin did not handle forwarded SIGTERM: %v", n, runErr)
+	}
+}
+
+func shouldNotForwardInterruptToPlugin(t *testing.T, n string) {
+	dir := createTestPlugin(t, "testcmd-int", "trap 'echo interrupted' INT\nkill -INT $PPID\nsleep 1\necho done")
+	defer os.RemoveAll(dir)
+	os.Args = []string{"testcmd", "int"}
+	var strBuilder strings.Builder
+	parser := cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background()))
+	parser.EnablePlugins(dir)
+	runErr := cli.NewRunner(parser, "v1", &strBuilder).Run()
+
+	if runErr != nil || strBuilder.String() != "done\n" {
+		t.Fail()
+		t.Log(n + ": plugin received forwarded SIGINT: " + strBuilder.String())
+	}
+}
+
+func shouldFindPluginOnPath(t *testing.T, n string) {
+	dir := createTestPlugin(t, "testcmd-path", "echo path")
+	defer os.RemoveAll(dir)
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	_ = os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	os.Args = []string{"testcmd", "path"}
+	var strBuilder strings.Builder
+	parser := cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background()))
+	parser.EnablePlugins()
+	runErr := cli.NewRunner(parser, "v1", &strBuilder).Run()
+
+	if runErr != nil || strBuilder.String() != "path\n" {
+		t.Fail()
+		t.Log(n + ": failed to run plugin from PATH")
+	}
+}
+
+func shouldResolvePluginDirsAfterRootOptions(t *testing.T, n string) {
+	dir := createTestPlugin(t, "testcmd-late", `echo "late $@"`)
+	defer os.RemoveAll(dir)
+	os.Args = []string{"testcmd", "-q", "--plugin-dir", dir, "late", "--quiet"}
+	pluginDir := ""
+	quiet := false
+	var strBuilder strings.Builder
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddStringArg(&pluginDir, &cli.ArgDefinition{Name: "plugin-dir", Required: true})
+	cmd.AddBoolArg(&quiet, &cli.ArgDefinition{Name: "quiet", ShortName: 'q'})
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.EnablePluginsFunc(func() []string {
+		return []string{pluginDir}
+	})
+	runErr := cli.NewRunner(parser, "v1", &strBuilder).Run()
+
+	if runErr != nil || !quiet || strBuilder.String() != "late --quiet\n" {
+		t.Fail()
+		t.Log(n + ": failed to run plugin from dir given by root option")
+	}
+}
+
+func shouldPreferBuiltinSubcommandOverPlugin(t *testing.T, n string) {
+	dir := createTestPlugin(t, "testcmd-foo", "echo plugin")
+	defer os.RemoveAll(dir)
+	os.Args = []string{"testcmd", "foo"}
+	runResult := 0
+	var strBuilder strings.Builder
+	cmd := cli.NewCommand("testcmd", context.Background())
+	sub := cli.NewCommand("foo", context.Background())
+	sub.AddRunFunc(func(context.Context, []string) { runResult = 1 })
+	cmd.AddSubcommand(sub)
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.EnablePlugins(dir)
+	runErr := cli.NewRunner(parser, "v1", &strBuilder).Run()
+
+	if runErr != nil || runResult != 1 || strBuilder.String() != "" {
+		t.Fail()
+		t.Log(n + ": plugin shadowed built-in subcommand")
+	}
+}
+
+func shouldNotRunPluginWhenPluginsDisabled(t *testing.T, n string) {
+	dir := createTestPlugin(t, "testcmd-hello", "echo hello")
+	defer os.RemoveAll(dir)
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	_ = os.Setenv("PATH", dir)
+	os.Args = []string{"testcmd", "hello"}
+	parser := cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background()))
+
+	if runErr := cli.NewRunner(parser, "v1", &strings.Builder{}).Run(); runErr == nil {
+		t.Fail()
+		t.Log(n + ": ran plugin without plugins enabled")
+	}
+}
+
+func shouldListPluginsInRootHelp(t *testing.T, n string) {
+	dir := createTestPlugin(t, "testcmd-deploy", "exit 0")
+	defer os.RemoveAll(dir)
+	os.Args = []string{"testcmd", "--help"}
+	var strBuilder strings.Builder
+	parser := cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background()))
+	parser.EnablePlugins(dir)
+	runErr := cli.NewRunner(parser, "v1", &strBuilder).Run()
+
+	if runErr != nil || !strings.Contains(strBuilder.String(), "Plugins:\n    deploy\n") {
+		t.Fail()
+		t.Log(n + ": did not list plugins in help")
+	}
+}
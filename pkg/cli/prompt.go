@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func NewPrompter(r io.Reader, w io.Writer) Prompter {
+	return &prompter{
+		catalog: NewCatalog(DetectLocale(nil)),
+		input:   r,
+		reader:  bufio.NewReader(r),
+		writer:  w,
+	}
+}
+
+func (p *prompter) SetCatalog(c Catalog) {
+	p.catalog = c
+}
+
+func (p *prompter) Interactive() bool {
+	file, ok := p.input.(*os.File)
+
+	return ok && isTerminal(file.Fd())
+}
+
+func (p *prompter) Prompt(q *Prompt) (string, error) {
+	if len(q.Choices) > 0 {
+		return p.promptChoice(q)
+	}
+
+	label := getPromptLabel(q)
+
+	if q.Default != "" && !q.Secret {
+		label += " [" + q.Default + "]"
+	}
+
+	if _, writeErr := fmt.Fprint(p.writer, label+": "); writeErr != nil {
+		return "", writeErr
+	}
+
+	answer, readErr := p.readLine(q.Secret)
+
+	if readErr != nil {
+		return "", readErr
+	}
+
+	if answer == "" {
+		return q.Default, nil
+	}
+
+	return answer, nil
+}
+
+func (p *prompter) promptChoice(q *Prompt) (string, error) {
+	var menuBuilder strings.Builder
+	defaultIndex := 0
+
+	menuBuilder.WriteString(getPromptLabel(q) + ":\n")
+
+	for i, choice := range q.Choices {
+		menuBuilder.WriteString(strings.Repeat(" ", 4) + strconv.Itoa(i+1) + ") " + choice + "\n")
+
+		if choice == q.Default {
+			defaultIndex = i + 1
+		}
+	}
+
+	if _, writeErr := fmt.Fprint(p.writer, menuBuilder.String()); writeErr != nil {
+		return "", writeErr
+	}
+
+	for {
+		label := p.catalog.Message(MsgChoiceLabel)
+
+		if defaultIndex > 0 {
+			label += " [" + strconv.Itoa(defaultIndex) + "]"
+		}
+
+		if _, writeErr := fmt.Fprint(p.writer, label+": "); writeErr != nil {
+			return "", writeErr
+		}
+
+		answer, readErr := p.readLine(q.Secret)
+
+		if readErr != nil {
+			return "", readErr
+		}
+
+		if answer == "" && defaultIndex > 0 {
+			return q.Default, nil
+		}
+
+		if index, indexErr := strconv.Atoi(answer); indexErr == nil && index > 0 && index <= len(q.Choices) {
+			return q.Choices[index-1], nil
+		}
+
+		if isValidChoice(answer, q.Choices) {
+			return answer, nil
+		}
+
+		if _, writeErr := fmt.Fprintln(p.writer, p.catalog.Message(MsgInvalidChoice, answer)); writeErr != nil {
+			return "", writeErr
+		}
+	}
+}
+
+func (p *prompter) readLine(s bool) (string, error) {
+	if s && p.Interactive() {
+		fd := p.input.(*os.File).Fd()
+		restoreEcho, echoErr := disableEcho(fd)
+
+		if echoErr != nil {
+			return "", echoErr
+		}
+
+		defer func() {
+			restoreEcho()
+			_, _ = fmt.Fprintln(p.writer)
+		}()
+	}
+
+	line, readErr := p.reader.ReadString('\n')
+
+	if readErr == io.EOF && line != "" {
+		readErr = nil
+	}
+
+	if readErr == io.EOF {
+		return "", p.catalog.Error(MsgNoPromptInput)
+	}
+
+	return strings.TrimRight(line, "\r\n"), readErr
+}
+
+func getPromptLabel(q *Prompt) string {
+	label := q.Name
+
+	if q.UsageText != "" {
+		label += " (" + q.UsageText + ")"
+	}
+
+	return label
+}
+
+func isValidChoice(v string, c []string) bool {
+	if len(c) == 0 {
+		return true
+	}
+
+	for _, choice := range c {
+		if v == choice {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatArgValue(v interface{}) string {
+	value := reflect.ValueOf(v)
+
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return ""
+	}
+
+	elem := value.Elem()
+
+	if elem.Kind() != reflect.Slice {
+		return fmt.Sprint(elem.Interface())
+	}
+
+	var items []string
+
+	for i := 0; i < elem.Len(); i++ {
+		items = append(items, fmt.Sprint(elem.Index(i).Interface()))
+	}
+
+	return strings.Join(items, ",")
+}
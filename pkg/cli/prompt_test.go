@@ -0,0 +1,97 @@
+package cli_test
+
+import (
+	"github.com/sebuckler/teel/pkg/cli"
+	"strings"
+	"testing"
+)
+
+func TestPrompter_Prompt(t *testing.T) {
+	for name, test := range getPrompterTestCases() {
+		test(t, name)
+	}
+}
+
+func getPrompterTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should not be interactive when input is not a terminal": shouldNotBeInteractiveWhenInputIsNotATerminal,
+		"should return default when answer is empty":             shouldReturnDefaultWhenAnswerIsEmpty,
+		"should return typed answer":                             shouldReturnTypedAnswer,
+		"should select choice by number or value":                shouldSelectChoiceByNumberOrValue,
+		"should reprompt on invalid choice":                      shouldRepromptOnInvalidChoice,
+		"should error when input is exhausted":                   shouldErrorWhenInputIsExhausted,
+	}
+}
+
+func shouldNotBeInteractiveWhenInputIsNotATerminal(t *testing.T, n string) {
+	prompter := cli.NewPrompter(strings.NewReader(""), &strings.Builder{})
+
+	if prompter.Interactive() {
+		t.Fail()
+		t.Log(n + ": reader input incorrectly reported as terminal")
+	}
+}
+
+func shouldReturnDefaultWhenAnswerIsEmpty(t *testing.T, n string) {
+	var output strings.Builder
+	prompter := cli.NewPrompter(strings.NewReader("\n"), &output)
+	answer, err := prompter.Prompt(&cli.Prompt{Name: "title", Default: "Home"})
+
+	if err != nil || answer != "Home" || output.String() != "title [Home]: " {
+		t.Fail()
+		t.Log(n + ": did not fall back to default answer")
+	}
+}
+
+func shouldReturnTypedAnswer(t *testing.T, n string) {
+	var output strings.Builder
+	prompter := cli.NewPrompter(strings.NewReader("s3cret\n"), &output)
+	answer, err := prompter.Prompt(&cli.Prompt{Name: "token", Default: "old", Secret: true})
+
+	if err != nil || answer != "s3cret" || strings.Contains(output.String(), "old") {
+		t.Fail()
+		t.Log(n + ": did not read answer or leaked secret default")
+	}
+}
+
+func shouldSelectChoiceByNumberOrValue(t *testing.T, n string) {
+	testCases := map[string]string{
+		"2\n":     "draft",
+		"draft\n": "draft",
+		"\n":      "published",
+	}
+
+	for input, expected := range testCases {
+		prompter := cli.NewPrompter(strings.NewReader(input), &strings.Builder{})
+		answer, err := prompter.Prompt(&cli.Prompt{
+			Choices: []string{"published", "draft"},
+			Default: "published",
+			Name:    "status",
+		})
+
+		if err != nil || answer != expected {
+			t.Fail()
+			t.Log(n + ": selected '" + answer + "' instead of '" + expected + "'")
+		}
+	}
+}
+
+func shouldRepromptOnInvalidChoice(t *testing.T, n string) {
+	var output strings.Builder
+	prompter := cli.NewPrompter(strings.NewReader("7\ndraft\n"), &output)
+	answer, err := prompter.Prompt(&cli.Prompt{Choices: []string{"published", "draft"}, Name: "status"})
+
+	if err != nil || answer != "draft" || !strings.Contains(output.String(), "invalid choice: 7") {
+		t.Fail()
+		t.Log(n + ": did not reprompt after invalid choice")
+	}
+}
+
+func shouldErrorWhenInputIsExhausted(t *testing.T, n string) {
+	prompter := cli.NewPrompter(strings.NewReader(""), &strings.Builder{})
+
+	if _, err := prompter.Prompt(&cli.Prompt{Name: "title"}); err == nil {
+		t.Fail()
+		t.Log(n + ": did not error on empty input")
+	}
+}
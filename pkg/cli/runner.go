@@ -1,54 +1,318 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"os"
 )
 
 func NewRunner(p Parser, v string, w io.Writer) Runner {
 	return &runner{
-		parser:  p,
-		version: v,
-		writer:  w,
+		colorMode:       ColorAuto,
+		errWriter:       os.Stderr,
+		input:           os.Stdin,
+		mode:            FullChain,
+		parser:          p,
+		theme:           DefaultTheme(),
+		versionInfo:     NewVersionInfo(v, "", ""),
+		versionTemplate: DefaultVersionTemplate,
+		writer:          w,
 	}
 }
 
+func ChainValues(ctx context.Context) map[string]interface{} {
+	if values, ok := ctx.Value(chainValuesKey{}).(map[string]interface{}); ok && values != nil {
+		return values
+	}
+
+	return map[string]interface{}{}
+}
+
+func Stderr(ctx context.Context) io.Writer {
+	if w, ok := ctx.Value(stderrKey{}).(io.Writer); ok && w != nil {
+		return w
+	}
+
+	return os.Stderr
+}
+
+func Stdin(ctx context.Context) io.Reader {
+	if r, ok := ctx.Value(stdinKey{}).(io.Reader); ok && r != nil {
+		return r
+	}
+
+	return os.Stdin
+}
+
+func Stdout(ctx context.Context) io.Writer {
+	if w, ok := ctx.Value(stdoutKey{}).(io.Writer); ok && w != nil {
+		return w
+	}
+
+	return os.Stdout
+}
+
+func (r *runner) AddMiddleware(m ...Middleware) {
+	r.middleware = append(r.middleware, m...)
+}
+
+func (r *runner) AddParseHook(h ...ParseHook) {
+	r.parseHooks = append(r.parseHooks, h...)
+}
+
+func (r *runner) SetColorMode(m ColorMode) {
+	r.colorMode = m
+}
+
+func (r *runner) SetEnv(e []string) {
+	r.env = e
+}
+
+func (r *runner) SetErrorWriter(w io.Writer) {
+	r.errWriter = w
+}
+
+func (r *runner) SetExecutionMode(m ExecutionMode) {
+	r.mode = m
+}
+
+func (r *runner) SetInput(i io.Reader) {
+	r.input = i
+}
+
+func (r *runner) SetTheme(t *Theme) {
+	r.theme = t
+}
+
+func (r *runner) SetVersionInfo(i *VersionInfo) {
+	r.versionInfo = i
+}
+
+func (r *runner) SetVersionTemplate(t string) {
+	r.versionTemplate = t
+}
+
 func (r *runner) Run() error {
+	return r.RunContext(context.Background())
+}
+
+func (r *runner) RunContext(ctx context.Context) error {
+	rootCmd, runErr := r.runSafely(ctx)
+
+	if runErr == nil {
+		return nil
+	}
+
+	var exitErr *ExitError
+
+	if errors.As(runErr, &exitErr) {
+		return runErr
+	}
+
+	var catalog Catalog
+	colorMode := r.colorMode
+
+	if rootCmd != nil {
+		catalog = rootCmd.Catalog
+		colorMode = r.getColorMode(rootCmd)
+	}
+
+	r.writeMessage(getCatalog(catalog).Message(MsgErrorLabel), runErr.Error(), colorMode, r.getErrorStyle)
+
+	return &ExitError{Code: 1, Err: runErr}
+}
+
+func (r *runner) runSafely(ctx context.Context) (c *parsedCommand, err error) {
+	defer recoverPanic(&err, "")
+
+	return r.run(ctx)
+}
+
+func (r *runner) run(ctx context.Context) (*parsedCommand, error) {
 	parsedCommands, parseErr := r.parser.Parse()
+	hookErr := r.runParseHooks(context.WithValue(ctx, secretValuesKey{}, getSecretValues(parsedCommands)))
 
 	if parseErr != nil {
-		return parseErr
+		return nil, parseErr
+	}
+
+	if hookErr != nil {
+		return nil, hookErr
 	}
 
 	if len(parsedCommands) == 0 {
-		return errors.New("no commands parsed")
+		return nil, getCatalog(nil).Error(MsgNoCommandsParsed)
 	}
 
 	rootCmd := parsedCommands[0]
 
 	if rootCmd == nil {
-		return errors.New("no root command parsed")
+		return nil, getCatalog(nil).Error(MsgNoRootCommandParsed)
+	}
+
+	if rootCmd.Plugin != nil {
+		return rootCmd, runPlugin(rootCmd.Plugin, r.input, r.writer, r.errWriter)
+	}
+
+	colorMode := r.getColorMode(rootCmd)
+
+	for _, warning := range rootCmd.Warnings {
+		r.writeMessage(getCatalog(rootCmd.Catalog).Message(MsgWarningLabel), warning, colorMode, r.getWarningStyle)
+	}
+
+	if rootCmd.SchemaMode {
+		encoder := json.NewEncoder(r.writer)
+		encoder.SetIndent("", "  ")
+
+		return rootCmd, encoder.Encode(newCommandSchema(rootCmd.HelpCommand))
+	}
+
+	if rootCmd.HelpTopic != nil {
+		return rootCmd, writeHelpTopic(rootCmd.HelpTopic, getCatalog(rootCmd.Catalog), r.writer)
 	}
 
 	if rootCmd.HelpMode {
 		helpCmd := rootCmd.HelpCommand
+		helpCmd.Theme = r.getTheme(colorMode, r.writer)
 
-		return helpCmd.HelpFunc(helpCmd, rootCmd.Syntax, r.writer)
+		return rootCmd, helpCmd.HelpFunc(helpCmd, rootCmd.Syntax, r.writer)
 	}
 
 	if rootCmd.VersionMode {
-		_, writeErr := r.writer.Write([]byte(rootCmd.Name + " " + r.version + "\n"))
+		versionInfo := *r.versionInfo
+		versionInfo.Name = rootCmd.Name
+
+		return rootCmd, writeVersion(&versionInfo, r.versionTemplate, rootCmd.OutputFormat, r.writer)
+	}
+
+	runCommands, selectErr := r.selectCommands(parsedCommands, getCatalog(rootCmd.Catalog))
+
+	if selectErr != nil {
+		return rootCmd, selectErr
+	}
+
+	return rootCmd, r.runCommands(ctx, parsedCommands, runCommands)
+}
+
+func (r *runner) runCommands(ctx context.Context, p []*parsedCommand, c []*parsedCommand) (err error) {
+	files := getFiles(p)
+
+	defer func() {
+		if closeErr := closeFiles(files); err == nil {
+			err = closeErr
+		}
+	}()
 
-		return writeErr
+	if openErr := openFiles(files, r.input, r.writer); openErr != nil {
+		return openErr
 	}
 
-	for _, cmd := range parsedCommands {
+	chainValues := map[string]interface{}{}
+	secretValues := getSecretValues(p)
+
+	for _, cmd := range c {
 		if cmd.Run == nil {
 			continue
 		}
 
-		cmd.Run(cmd.Context, cmd.Operands)
+		cmdCtx := mergeContext(cmd.Context, ctx)
+
+		if r.mode != ChainedVerbs {
+			chainValues = map[string]interface{}{}
+		}
+
+		cmdCtx = context.WithValue(cmdCtx, chainValuesKey{}, chainValues)
+
+		cmdCtx = context.WithValue(cmdCtx, commandPathKey{}, getCommandPath(cmd.command))
+		cmdCtx = context.WithValue(cmdCtx, secretValuesKey{}, secretValues)
+		cmdCtx = context.WithValue(cmdCtx, stderrKey{}, r.errWriter)
+		cmdCtx = context.WithValue(cmdCtx, stdinKey{}, r.input)
+		cmdCtx = context.WithValue(cmdCtx, stdoutKey{}, r.writer)
+
+		if runErr := r.runHandler(cmdCtx, cmd); runErr != nil {
+			return runErr
+		}
 	}
 
 	return nil
 }
+
+func (r *runner) runParseHooks(ctx context.Context) error {
+	for _, hook := range r.parseHooks {
+		if err := hook(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *runner) runHandler(ctx context.Context, c *parsedCommand) (err error) {
+	defer recoverPanic(&err, CommandPath(ctx))
+
+	return r.getHandler(c)(ctx, c.Operands)
+}
+
+func (r *runner) getColorMode(c *parsedCommand) ColorMode {
+	if mode, ok := ParseColorMode(c.Color); ok {
+		return mode
+	}
+
+	return r.colorMode
+}
+
+func (r *runner) getTheme(m ColorMode, w io.Writer) *Theme {
+	if r.theme == nil || !isColorEnabled(m, w, r.env) {
+		return &Theme{}
+	}
+
+	return r.theme
+}
+
+func (r *runner) getErrorStyle(t *Theme) Style {
+	return t.Error
+}
+
+func (r *runner) getWarningStyle(t *Theme) Style {
+	return t.Warning
+}
+
+func (r *runner) writeMessage(l string, m string, c ColorMode, s func(t *Theme) Style) {
+	style := s(r.getTheme(c, r.errWriter))
+
+	_, _ = fmt.Fprintf(r.errWriter, "%s %s\n", style.Apply(l), m)
+}
+
+func (r *runner) selectCommands(c []*parsedCommand, m Catalog) ([]*parsedCommand, error) {
+	switch r.mode {
+	case FullChain:
+		return c, nil
+	case LeafOnly:
+		leaves := getLeafCommands(c)
+
+		if len(leaves) > 1 {
+			return nil, m.Error(MsgUnexpectedCommand, leaves[1].Name)
+		}
+
+		return leaves, nil
+	case ChainedVerbs:
+		return getLeafCommands(c), nil
+	default:
+		return nil, m.Error(MsgUnsupportedExecMode)
+	}
+}
+
+func getLeafCommands(c []*parsedCommand) []*parsedCommand {
+	var leaves []*parsedCommand
+
+	for _, cmd := range c {
+		if len(cmd.Subcommands) == 0 {
+			leaves = append(leaves, cmd)
+		}
+	}
+
+	return leaves
+}
@@ -3,6 +3,8 @@ package cli_test
 import (
 	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"github.com/sebuckler/teel/pkg/cli"
 	"os"
 	"strings"
@@ -22,6 +24,15 @@ func getRunnerTestCases() map[string]func(t *testing.T, n string) {
 		"should print help text when help mode is true":           shouldPrintTextWhenHelpModeIsTrue,
 		"should run root cmd run":                                 shouldRunRootCmdRun,
 		"should run subcommand runs":                              shouldRunSubcommandRuns,
+		"should run only leaf command in leaf only mode":          shouldRunOnlyLeafCommandInLeafOnlyMode,
+		"should error on chained verbs in leaf only mode":         shouldErrorOnChainedVerbsInLeafOnlyMode,
+		"should share context between chained verbs":              shouldShareContextBetweenChainedVerbs,
+		"should scope chain values to command in full chain mode": shouldScopeChainValuesToCommandInFullChainMode,
+		"should return empty chain values outside runner":         shouldReturnEmptyChainValuesOutsideRunner,
+		"should print schema when help json arg exists":           shouldPrintSchemaWhenHelpJSONArgExists,
+		"should pass run context cancellation to run func":        shouldPassRunContextCancellationToRunFunc,
+		"should keep command context values with run context":     shouldKeepCommandContextValuesWithRunContext,
+		"should report run error with non zero exit code":         shouldReportRunErrorWithNonZeroExitCode,
 	}
 }
 
@@ -117,3 +128,170 @@ func shouldRunSubcommandRuns(t *testing.T, n string) {
 		t.Log(n + ": incorrectly errored on subcommand runs")
 	}
 }
+
+func shouldRunOnlyLeafCommandInLeafOnlyMode(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "foo"}
+	var runResults []string
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddRunFunc(func(context.Context, []string) {
+		runResults = append(runResults, "testcmd")
+	})
+	sub := cli.NewCommand("foo", context.Background())
+	sub.AddRunFunc(func(context.Context, []string) {
+		runResults = append(runResults, "foo")
+	})
+	cmd.AddSubcommand(sub)
+	runner := cli.NewRunner(cli.NewParser(cli.GNU, cmd), "v1", &strings.Builder{})
+	runner.SetExecutionMode(cli.LeafOnly)
+	runErr := runner.Run()
+
+	if runErr != nil || len(runResults) != 1 || runResults[0] != "foo" {
+		t.Fail()
+		t.Log(n + ": did not run only the leaf command")
+	}
+}
+
+func shouldErrorOnChainedVerbsInLeafOnlyMode(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "foo", "bar"}
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddSubcommand(cli.NewCommand("foo", context.Background()), cli.NewCommand("bar", context.Background()))
+	runner := cli.NewRunner(cli.NewParser(cli.GNU, cmd), "v1", &strings.Builder{})
+	runner.SetExecutionMode(cli.LeafOnly)
+
+	if runErr := runner.Run(); runErr == nil {
+		t.Fail()
+		t.Log(n + ": did not error on multiple leaf commands")
+	}
+}
+
+func shouldShareContextBetweenChainedVerbs(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "build", "publish"}
+	published := ""
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddRunFunc(func(context.Context, []string) {
+		t.Fail()
+		t.Log(n + ": should not have run root command")
+	})
+	build := cli.NewCommand("build", context.Background())
+	build.AddRunFunc(func(ctx context.Context, _ []string) {
+		cli.ChainValues(ctx)["output"] = "public"
+	})
+	publish := cli.NewCommand("publish", context.Background())
+	publish.AddRunFunc(func(ctx context.Context, _ []string) {
+		published, _ = cli.ChainValues(ctx)["output"].(string)
+	})
+	cmd.AddSubcommand(build, publish)
+	runner := cli.NewRunner(cli.NewParser(cli.GNU, cmd), "v1", &strings.Builder{})
+	runner.SetExecutionMode(cli.ChainedVerbs)
+	runErr := runner.Run()
+
+	if runErr != nil || published != "public" {
+		t.Fail()
+		t.Log(n + ": chained verbs did not share context")
+	}
+}
+
+func shouldScopeChainValuesToCommandInFullChainMode(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "build"}
+	var rootValues, buildValues map[string]interface{}
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddRunFunc(func(ctx context.Context, _ []string) {
+		rootValues = cli.ChainValues(ctx)
+		rootValues["output"] = "public"
+	})
+	build := cli.NewCommand("build", context.Background())
+	build.AddRunFunc(func(ctx context.Context, _ []string) {
+		buildValues = cli.ChainValues(ctx)
+	})
+	cmd.AddSubcommand(build)
+	runErr := cli.NewRunner(cli.NewParser(cli.GNU, cmd), "v1", &strings.Builder{}).Run()
+
+	if runErr != nil || rootValues["output"] != "public" || buildValues == nil || len(buildValues) != 0 {
+		t.Fail()
+		t.Log(n + ": chain values not writable or shared between commands in full chain mode")
+	}
+}
+
+func shouldReturnEmptyChainValuesOutsideRunner(t *testing.T, n string) {
+	values := cli.ChainValues(context.Background())
+	values["output"] = "public"
+
+	if len(values) != 1 {
+		t.Fail()
+		t.Log(n + ": chain values not writable outside runner")
+	}
+}
+
+func shouldPrintSchemaWhenHelpJSONArgExists(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "foo", "--help-json"}
+	var strBuilder strings.Builder
+	cmd := cli.NewCommand("testcmd", context.Background())
+	sub := cli.NewCommand("foo", context.Background())
+	sub.AddRunFunc(func(context.Context, []string) {
+		t.Fail()
+		t.Log(n + ": should not have run command")
+	})
+	cmd.AddSubcommand(sub)
+	runner := cli.NewRunner(cli.NewParser(cli.GNU, cmd), "v1", &strBuilder)
+	runErr := runner.Run()
+	schema := &cli.CommandSchema{}
+	jsonErr := json.Unmarshal([]byte(strBuilder.String()), schema)
+
+	if runErr != nil || jsonErr != nil || schema.Name != "foo" {
+		t.Fail()
+		t.Log(n + ": failed to print command schema")
+	}
+}
+
+func shouldPassRunContextCancellationToRunFunc(t *testing.T, n string) {
+	var runErr error
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddRunFunc(func(ctx context.Context, _ []string) {
+		<-ctx.Done()
+		runErr = ctx.Err()
+	})
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.SetArgs([]string{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := cli.NewRunner(parser, "v1", &strings.Builder{}).RunContext(ctx); err != nil || runErr != context.Canceled {
+		t.Fail()
+		t.Log(n + ": did not pass run context cancellation")
+	}
+}
+
+type runnerTestKey struct{}
+
+func shouldKeepCommandContextValuesWithRunContext(t *testing.T, n string) {
+	value := ""
+	cmd := cli.NewCommand("testcmd", context.WithValue(context.Background(), runnerTestKey{}, "command"))
+	cmd.AddRunFunc(func(ctx context.Context, _ []string) {
+		value, _ = ctx.Value(runnerTestKey{}).(string)
+	})
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.SetArgs([]string{})
+
+	if err := cli.NewRunner(parser, "v1", &strings.Builder{}).RunContext(context.Background()); err != nil || value != "command" {
+		t.Fail()
+		t.Log(n + ": did not keep command context values")
+	}
+}
+
+func shouldReportRunErrorWithNonZeroExitCode(t *testing.T, n string) {
+	os.Args = []string{"testcmd"}
+	var stderr strings.Builder
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddRunErrorFunc(func(context.Context, []string) error {
+		return errors.New("name must be provided")
+	})
+	runner := cli.NewRunner(cli.NewParser(cli.GNU, cmd), "v1", &strings.Builder{})
+	runner.SetErrorWriter(&stderr)
+	runErr := runner.Run()
+	var exitErr *cli.ExitError
+
+	if !errors.As(runErr, &exitErr) || exitErr.Code != 1 || stderr.String() != "Error: name must be provided\n" {
+		t.Fail()
+		t.Log(n + ": run error not reported: " + stderr.String())
+	}
+}
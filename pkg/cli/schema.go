@@ -0,0 +1,91 @@
+package cli
+
+import "reflect"
+
+func NewCommandSchema(b CommandBuilder) *CommandSchema {
+	return newCommandSchema(b.Build())
+}
+
+func newCommandSchema(c *command) *CommandSchema {
+	schema := &CommandSchema{
+		Name:        c.Name,
+		UsageText:   c.UsageText,
+		Options:     []*OptionSchema{},
+		Operands:    []*OperandSchema{},
+		Subcommands: []*CommandSchema{},
+	}
+
+	for _, arg := range c.Args {
+		option := &OptionSchema{
+			Name:       arg.Name,
+			Type:       getArgType(arg.Value),
+			Default:    getArgDefault(arg.Value),
+			Choices:    arg.Choices,
+			Repeatable: arg.Repeatable,
+			Required:   arg.Required,
+			Secret:     arg.Secret,
+			UsageText:  arg.UsageText,
+		}
+
+		if arg.Secret && option.Default != nil && !isZeroValue(option.Default) {
+			option.Default = SecretMask
+		}
+
+		if arg.ShortName > 0 {
+			option.ShortName = string(arg.ShortName)
+		}
+
+		schema.Options = append(schema.Options, option)
+	}
+
+	for _, operand := range c.Operands {
+		schema.Operands = append(schema.Operands, &OperandSchema{
+			Name:      operand.Name,
+			Type:      "string",
+			Default:   formatArgValue(operand.Value),
+			Choices:   operand.Choices,
+			Required:  operand.Required,
+			UsageText: operand.UsageText,
+		})
+	}
+
+	for _, subCmd := range c.Subcommands {
+		schema.Subcommands = append(schema.Subcommands, newCommandSchema(subCmd))
+	}
+
+	return schema
+}
+
+func getArgType(v interface{}) string {
+	if _, ok := v.(*File); ok {
+		return "file"
+	}
+
+	valueType := reflect.TypeOf(v)
+
+	if valueType == nil || valueType.Kind() != reflect.Ptr {
+		return ""
+	}
+
+	return valueType.Elem().String()
+}
+
+func getArgDefault(v interface{}) interface{} {
+	if file, ok := v.(*File); ok {
+		return file.Path
+	}
+
+	value := reflect.ValueOf(v)
+
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return nil
+	}
+
+	return value.Elem().Interface()
+}
+
+func isZeroValue(v interface{}) bool {
+	value := reflect.ValueOf(v)
+
+	return !value.IsValid() || value.IsZero()
+}
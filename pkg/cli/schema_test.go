@@ -0,0 +1,84 @@
+package cli_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sebuckler/teel/pkg/cli"
+	"testing"
+)
+
+func TestNewCommandSchema(t *testing.T) {
+	for name, test := range getSchemaTestCases() {
+		test(t, name)
+	}
+}
+
+func getSchemaTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should describe command tree":       shouldDescribeCommandTree,
+		"should describe option definitions": shouldDescribeOptionDefinitions,
+		"should serialize to JSON":           shouldSerializeToJSON,
+	}
+}
+
+func shouldDescribeCommandTree(t *testing.T, n string) {
+	cmd := cli.NewCommand("teel", context.Background())
+	cmd.AddUsageText("manage static sites")
+	sub := cli.NewCommand("page", context.Background())
+	title := ""
+	sub.AddOperand(&title, &cli.OperandDefinition{Name: "title", Required: true, UsageText: "page title"})
+	cmd.AddSubcommand(sub)
+	schema := cli.NewCommandSchema(cmd)
+
+	if schema.Name != "teel" || schema.UsageText != "manage static sites" || len(schema.Subcommands) != 1 {
+		t.Fail()
+		t.Log(n + ": root command incorrectly described")
+
+		return
+	}
+
+	operands := schema.Subcommands[0].Operands
+
+	if len(operands) != 1 || operands[0].Name != "title" || !operands[0].Required || operands[0].Type != "string" {
+		t.Fail()
+		t.Log(n + ": subcommand operands incorrectly described")
+	}
+}
+
+func shouldDescribeOptionDefinitions(t *testing.T, n string) {
+	cmd := cli.NewCommand("teel", context.Background())
+	port := 8080
+	cmd.AddIntArg(&port, &cli.ArgDefinition{
+		Name:      "port",
+		ShortName: 'p',
+		Required:  true,
+		UsageText: "port to listen on",
+	})
+	option := cli.NewCommandSchema(cmd).Options[0]
+
+	if option.Name != "port" || option.ShortName != "p" || option.Type != "int" || option.Default != 8080 ||
+		!option.Required || option.UsageText != "port to listen on" {
+		t.Fail()
+		t.Log(n + ": option incorrectly described")
+	}
+}
+
+func shouldSerializeToJSON(t *testing.T, n string) {
+	cmd := cli.NewCommand("teel", context.Background())
+	tags := []string{"a", "b"}
+	cmd.AddStringListArg(&tags, &cli.ArgDefinition{Name: "tags", Choices: []string{"a", "b", "c"}})
+	data, err := json.Marshal(cli.NewCommandSchema(cmd))
+	expected := `{"name":"teel","options":[{"name":"tags","type":"[]string","default":["a","b"],` +
+		`"choices":["a","b","c"],"repeatable":false,"required":false},` +
+		`{"name":"help","shortName":"h","type":"bool","default":false,"repeatable":true,"required":false,` +
+		`"usageText":"display usage information for this command"},` +
+		`{"name":"version","shortName":"v","type":"bool","default":false,"repeatable":true,"required":false,` +
+		`"usageText":"display the version for the utility"},` +
+		`{"name":"color","type":"string","default":"","choices":["auto","always","never"],"repeatable":true,` +
+		`"required":false,"usageText":"colourise output: auto, always or never"}],"operands":[],"subcommands":[]}`
+
+	if err != nil || string(data) != expected {
+		t.Fail()
+		t.Log(n + ": schema incorrectly serialized: " + string(data))
+	}
+}
@@ -0,0 +1,41 @@
+package cli
+
+import "context"
+
+const SecretMask = "********"
+
+type secretValuesKey struct{}
+
+func SecretValues(ctx context.Context) []string {
+	values, _ := ctx.Value(secretValuesKey{}).([]string)
+
+	return values
+}
+
+func getSecretValues(c []*parsedCommand) []string {
+	var values []string
+
+	for _, cmd := range c {
+		for _, arg := range cmd.parsedArgs {
+			if !arg.secret {
+				continue
+			}
+
+			for _, value := range arg.value {
+				if value != "" {
+					values = append(values, value)
+				}
+			}
+		}
+	}
+
+	return values
+}
+
+func maskArgValue(a *parsedArg, v string) string {
+	if a != nil && a.secret && v != "" {
+		return SecretMask
+	}
+
+	return v
+}
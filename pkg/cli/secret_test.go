@@ -0,0 +1,130 @@
+package cli_test
+
+import (
+	"context"
+	"github.com/sebuckler/teel/pkg/cli"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSecretArgs(t *testing.T) {
+	for name, test := range getSecretTestCases() {
+		test(t, name)
+	}
+}
+
+func getSecretTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should mask secret value in invalid argument error": shouldMaskSecretValueInInvalidArgumentError,
+		"should mask secret value in separated value error":  shouldMaskSecretValueInSeparatedValueError,
+		"should not mask non secret value in error":          shouldNotMaskNonSecretValueInError,
+		"should mask secret default in schema":               shouldMaskSecretDefaultInSchema,
+		"should expose secret values to run context":         shouldExposeSecretValuesToRunContext,
+		"should expose secret values to parse hooks":         shouldExposeSecretValuesToParseHooks,
+	}
+}
+
+func runSecretTestCommand(c cli.CommandBuilder, a []string) (string, error) {
+	var stderr strings.Builder
+	parser := cli.NewParser(cli.GNU, c)
+	parser.SetArgs(a)
+	parser.SetEnv([]string{})
+	runner := cli.NewRunner(parser, "v1", &strings.Builder{})
+	runner.SetErrorWriter(&stderr)
+	runErr := runner.Run()
+
+	return stderr.String(), runErr
+}
+
+func shouldMaskSecretValueInInvalidArgumentError(t *testing.T, n string) {
+	var token int
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddIntArg(&token, &cli.ArgDefinition{Name: "token", Required: true, Secret: true})
+	cmd.AddRunFunc(func(context.Context, []string) {})
+	stderr, runErr := runSecretTestCommand(cmd, []string{"--token=hunter2"})
+
+	if runErr == nil || strings.Contains(stderr, "hunter2") || !strings.Contains(stderr, cli.SecretMask) {
+		t.Fail()
+		t.Log(n + ": failed to mask secret value, got: " + stderr)
+	}
+}
+
+func shouldMaskSecretValueInSeparatedValueError(t *testing.T, n string) {
+	var verbose bool
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddBoolArg(&verbose, &cli.ArgDefinition{Name: "verbose", Secret: true})
+	cmd.AddRunFunc(func(context.Context, []string) {})
+	stderr, runErr := runSecretTestCommand(cmd, []string{"--verbose=hunter2=x"})
+
+	if runErr == nil || strings.Contains(stderr, "hunter2") {
+		t.Fail()
+		t.Log(n + ": failed to mask secret value, got: " + stderr)
+	}
+}
+
+func shouldNotMaskNonSecretValueInError(t *testing.T, n string) {
+	var count int
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddIntArg(&count, &cli.ArgDefinition{Name: "count", Required: true})
+	cmd.AddRunFunc(func(context.Context, []string) {})
+	stderr, runErr := runSecretTestCommand(cmd, []string{"--count=many"})
+
+	if runErr == nil || !strings.Contains(stderr, "many") {
+		t.Fail()
+		t.Log(n + ": expected non secret value in error, got: " + stderr)
+	}
+}
+
+func shouldMaskSecretDefaultInSchema(t *testing.T, n string) {
+	token := "hunter2"
+	empty := ""
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddStringArg(&token, &cli.ArgDefinition{Name: "token", Secret: true})
+	cmd.AddStringArg(&empty, &cli.ArgDefinition{Name: "key", Secret: true})
+	options := cli.NewCommandSchema(cmd).Options
+
+	if options[0].Default != cli.SecretMask || !options[0].Secret || options[1].Default != "" {
+		t.Fail()
+		t.Log(n + ": failed to mask secret default in schema")
+	}
+}
+
+func shouldExposeSecretValuesToRunContext(t *testing.T, n string) {
+	var token string
+	var name string
+	var values []string
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddStringArg(&token, &cli.ArgDefinition{Name: "token", Required: true, Secret: true})
+	cmd.AddStringArg(&name, &cli.ArgDefinition{Name: "name", Required: true})
+	cmd.AddRunFunc(func(ctx context.Context, o []string) {
+		values = cli.SecretValues(ctx)
+	})
+	_, runErr := runSecretTestCommand(cmd, []string{"--token=hunter2", "--name=site"})
+
+	if runErr != nil || !reflect.DeepEqual(values, []string{"hunter2"}) {
+		t.Fail()
+		t.Log(n + ": failed to expose secret values to run context")
+	}
+}
+
+func shouldExposeSecretValuesToParseHooks(t *testing.T, n string) {
+	var token string
+	var secrets []string
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddStringArg(&token, &cli.ArgDefinition{Name: "token", Required: true, Secret: true})
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.SetArgs([]string{"--token=hunter2", "--version"})
+	parser.SetEnv([]string{})
+	runner := cli.NewRunner(parser, "v1", &strings.Builder{})
+	runner.AddParseHook(func(ctx context.Context) error {
+		secrets = cli.SecretValues(ctx)
+
+		return nil
+	})
+
+	if runErr := runner.Run(); runErr != nil || !reflect.DeepEqual(secrets, []string{"hunter2"}) {
+		t.Fail()
+		t.Log(n + ": secret values not exposed to parse hook")
+	}
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"io"
+	"os"
+)
+
+func DefaultTheme() *Theme {
+	return &Theme{
+		Command: "36",
+		Error:   "1;31",
+		Heading: "1",
+		Option:  "32",
+		Warning: "1;33",
+	}
+}
+
+func ParseColorMode(m string) (ColorMode, bool) {
+	switch m {
+	case "auto":
+		return ColorAuto, true
+	case "always":
+		return ColorAlways, true
+	case "never":
+		return ColorNever, true
+	default:
+		return ColorAuto, false
+	}
+}
+
+func (s Style) Apply(t string) string {
+	if s == "" || t == "" {
+		return t
+	}
+
+	return "\x1b[" + string(s) + "m" + t + "\x1b[0m"
+}
+
+func getTheme(t *Theme) *Theme {
+	if t == nil {
+		return &Theme{}
+	}
+
+	return t
+}
+
+func isColorEnabled(m ColorMode, w io.Writer, e []string) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if getenv(e, "NO_COLOR") != "" || getenv(e, "TERM") == "dumb" {
+		return false
+	}
+
+	file, ok := w.(*os.File)
+
+	return ok && isTerminal(file.Fd())
+}
@@ -0,0 +1,149 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"github.com/sebuckler/teel/pkg/cli"
+	"strings"
+	"testing"
+)
+
+func TestRunner_Color(t *testing.T) {
+	for name, test := range getColorTestCases() {
+		test(t, name)
+	}
+}
+
+func getColorTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should style help when color is always":        shouldStyleHelpWhenColorIsAlways,
+		"should not style help when not a terminal":     shouldNotStyleHelpWhenNotTerminal,
+		"should not style help when color is never":     shouldNotStyleHelpWhenColorIsNever,
+		"should prefer color option over runner mode":   shouldPreferColorOptionOverRunnerMode,
+		"should not style help when no color is set":    shouldNotStyleHelpWhenNoColorIsSet,
+		"should use custom theme":                       shouldUseCustomTheme,
+		"should write styled error to error writer":     shouldWriteStyledErrorToErrorWriter,
+		"should write warning when output is ignored":   shouldWriteWarningWhenOutputIsIgnored,
+		"should error on invalid color option argument": shouldErrorOnInvalidColorOptionArgument,
+	}
+}
+
+func newColorTestRunner(a []string, e []string, w *strings.Builder, ew *strings.Builder) cli.Runner {
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddSubcommand(cli.NewCommand("foo", context.Background()))
+	parser := cli.NewParser(cli.GNU, cmd)
+	parser.SetArgs(a)
+	parser.SetEnv(e)
+	runner := cli.NewRunner(parser, "v1", w)
+	runner.SetEnv(e)
+	runner.SetErrorWriter(ew)
+
+	return runner
+}
+
+func shouldStyleHelpWhenColorIsAlways(t *testing.T, n string) {
+	var stdout strings.Builder
+	runner := newColorTestRunner([]string{"--color=always", "--help"}, []string{}, &stdout, &strings.Builder{})
+	runErr := runner.Run()
+
+	if runErr != nil || !strings.Contains(stdout.String(), "\x1b[1mUsage:\x1b[0m") ||
+		!strings.Contains(stdout.String(), "\x1b[36mfoo\x1b[0m") {
+		t.Fail()
+		t.Log(n + ": failed to style help text")
+	}
+}
+
+func shouldNotStyleHelpWhenNotTerminal(t *testing.T, n string) {
+	var stdout strings.Builder
+	runner := newColorTestRunner([]string{"--help"}, []string{}, &stdout, &strings.Builder{})
+	runErr := runner.Run()
+
+	if runErr != nil || strings.Contains(stdout.String(), "\x1b[") {
+		t.Fail()
+		t.Log(n + ": styled help text written to non-terminal")
+	}
+}
+
+func shouldNotStyleHelpWhenColorIsNever(t *testing.T, n string) {
+	var stdout strings.Builder
+	runner := newColorTestRunner([]string{"--color=never", "--help"}, []string{}, &stdout, &strings.Builder{})
+	runner.SetColorMode(cli.ColorAlways)
+	runErr := runner.Run()
+
+	if runErr != nil || strings.Contains(stdout.String(), "\x1b[") {
+		t.Fail()
+		t.Log(n + ": styled help text when color is never")
+	}
+}
+
+func shouldPreferColorOptionOverRunnerMode(t *testing.T, n string) {
+	var stdout strings.Builder
+	runner := newColorTestRunner([]string{"--color", "--help"}, []string{}, &stdout, &strings.Builder{})
+	runner.SetColorMode(cli.ColorNever)
+	runErr := runner.Run()
+
+	if runErr != nil || !strings.Contains(stdout.String(), "\x1b[") {
+		t.Fail()
+		t.Log(n + ": did not prefer color option over runner mode")
+	}
+}
+
+func shouldNotStyleHelpWhenNoColorIsSet(t *testing.T, n string) {
+	var stdout strings.Builder
+	runner := newColorTestRunner([]string{"--color=auto", "--help"}, []string{"NO_COLOR=1"}, &stdout, &strings.Builder{})
+	runErr := runner.Run()
+
+	if runErr != nil || strings.Contains(stdout.String(), "\x1b[") {
+		t.Fail()
+		t.Log(n + ": styled help text when NO_COLOR is set")
+	}
+}
+
+func shouldUseCustomTheme(t *testing.T, n string) {
+	var stdout strings.Builder
+	runner := newColorTestRunner([]string{"--help"}, []string{}, &stdout, &strings.Builder{})
+	runner.SetColorMode(cli.ColorAlways)
+	runner.SetTheme(&cli.Theme{Heading: "4"})
+	runErr := runner.Run()
+
+	if runErr != nil || !strings.Contains(stdout.String(), "\x1b[4mUsage:\x1b[0m") ||
+		strings.Contains(stdout.String(), "\x1b[36m") {
+		t.Fail()
+		t.Log(n + ": failed to use custom theme")
+	}
+}
+
+func shouldWriteStyledErrorToErrorWriter(t *testing.T, n string) {
+	var stderr strings.Builder
+	runner := newColorTestRunner([]string{"--bogus"}, []string{}, &strings.Builder{}, &stderr)
+	runner.SetColorMode(cli.ColorAlways)
+	runErr := runner.Run()
+	var exitErr *cli.ExitError
+
+	if !errors.As(runErr, &exitErr) || exitErr.Code != 1 ||
+		!strings.HasPrefix(stderr.String(), "\x1b[1;31mError:\x1b[0m ") {
+		t.Fail()
+		t.Log(n + ": failed to write styled error")
+	}
+}
+
+func shouldWriteWarningWhenOutputIsIgnored(t *testing.T, n string) {
+	var stderr strings.Builder
+	runner := newColorTestRunner([]string{"--output=json"}, []string{}, &strings.Builder{}, &stderr)
+	runErr := runner.Run()
+
+	if runErr != nil || !strings.HasPrefix(stderr.String(), "Warning: ") {
+		t.Fail()
+		t.Log(n + ": failed to write warning")
+	}
+}
+
+func shouldErrorOnInvalidColorOptionArgument(t *testing.T, n string) {
+	var stderr strings.Builder
+	runner := newColorTestRunner([]string{"--color=rainbow"}, []string{}, &strings.Builder{}, &stderr)
+
+	if runErr := runner.Run(); runErr == nil || !strings.Contains(stderr.String(), "rainbow") {
+		t.Fail()
+		t.Log(n + ": did not error on invalid color option argument")
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package cli
+
+import "errors"
+
+func isTerminal(_ uintptr) bool {
+	return false
+}
+
+func disableEcho(_ uintptr) (func(), error) {
+	return nil, errors.New("masked input is not supported on this platform")
+}
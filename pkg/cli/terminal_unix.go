@@ -0,0 +1,42 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package cli
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func isTerminal(fd uintptr) bool {
+	var termios syscall.Termios
+
+	return ioctlTermios(fd, ioctlGetTermios, &termios) == nil
+}
+
+func disableEcho(fd uintptr) (func(), error) {
+	var termios syscall.Termios
+
+	if getErr := ioctlTermios(fd, ioctlGetTermios, &termios); getErr != nil {
+		return nil, getErr
+	}
+
+	original := termios
+	termios.Lflag &^= syscall.ECHO
+
+	if setErr := ioctlTermios(fd, ioctlSetTermios, &termios); setErr != nil {
+		return nil, setErr
+	}
+
+	return func() {
+		_ = ioctlTermios(fd, ioctlSetTermios, &original)
+	}, nil
+}
+
+func ioctlTermios(fd uintptr, r uintptr, t *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, r, uintptr(unsafe.Pointer(t))); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
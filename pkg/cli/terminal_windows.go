@@ -0,0 +1,40 @@
+//go:build windows
+// +build windows
+
+package cli
+
+import "syscall"
+
+const enableEchoInput = 0x0004
+
+var setConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+func isTerminal(fd uintptr) bool {
+	var mode uint32
+
+	return syscall.GetConsoleMode(syscall.Handle(fd), &mode) == nil
+}
+
+func disableEcho(fd uintptr) (func(), error) {
+	var mode uint32
+
+	if getErr := syscall.GetConsoleMode(syscall.Handle(fd), &mode); getErr != nil {
+		return nil, getErr
+	}
+
+	if setErr := setConsoleModeFlags(fd, mode&^enableEchoInput); setErr != nil {
+		return nil, setErr
+	}
+
+	return func() {
+		_ = setConsoleModeFlags(fd, mode)
+	}, nil
+}
+
+func setConsoleModeFlags(fd uintptr, m uint32) error {
+	if ok, _, callErr := setConsoleMode.Call(fd, uintptr(m)); ok == 0 {
+		return callErr
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"text/template"
+)
+
+const DefaultVersionTemplate = "{{.Name}} {{.Version}}\n"
+
+func NewVersionInfo(v string, c string, d string) *VersionInfo {
+	info := &VersionInfo{
+		Version:   v,
+		Commit:    c,
+		BuildDate: d,
+		Dirty:     strings.HasSuffix(v, "-dirty"),
+		GoVersion: runtime.Version(),
+	}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+
+	if !ok {
+		return info
+	}
+
+	if info.Version == "" && buildInfo.Main.Version != "(devel)" {
+		info.Version = buildInfo.Main.Version
+	}
+
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.BuildDate == "" {
+				info.BuildDate = setting.Value
+			}
+		case "vcs.modified":
+			if v == "" {
+				info.Dirty = setting.Value == "true"
+			}
+		}
+	}
+
+	for _, dep := range buildInfo.Deps {
+		module := &ModuleVersion{
+			Path:    dep.Path,
+			Version: dep.Version,
+			Sum:     dep.Sum,
+		}
+
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			module.Version = dep.Replace.Version
+			module.Sum = dep.Replace.Sum
+		}
+
+		info.Dependencies = append(info.Dependencies, module)
+	}
+
+	return info
+}
+
+func writeVersion(i *VersionInfo, t string, f string, w io.Writer) error {
+	if f == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(i)
+	}
+
+	versionTemplate, parseErr := template.New("version").Parse(t)
+
+	if parseErr != nil {
+		return parseErr
+	}
+
+	return versionTemplate.Execute(w, i)
+}
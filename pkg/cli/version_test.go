@@ -0,0 +1,113 @@
+package cli_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sebuckler/teel/pkg/cli"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestNewVersionInfo(t *testing.T) {
+	for name, test := range getVersionTestCases() {
+		test(t, name)
+	}
+}
+
+func getVersionTestCases() map[string]func(t *testing.T, n string) {
+	return map[string]func(t *testing.T, n string){
+		"should prefer build flag values":              shouldPreferBuildFlagValues,
+		"should mark dirty versions":                   shouldMarkDirtyVersions,
+		"should print name and version by default":     shouldPrintNameAndVersionByDefault,
+		"should print version with custom template":    shouldPrintVersionWithCustomTemplate,
+		"should print version as JSON":                 shouldPrintVersionAsJSON,
+		"should error on invalid version output value": shouldErrorOnInvalidVersionOutputValue,
+		"should bind command output option":            shouldBindCommandOutputOption,
+	}
+}
+
+func shouldPreferBuildFlagValues(t *testing.T, n string) {
+	info := cli.NewVersionInfo("v1.2.3", "abc123", "2020-05-17T21:25:48Z")
+
+	if info.Version != "v1.2.3" || info.Commit != "abc123" || info.BuildDate != "2020-05-17T21:25:48Z" ||
+		info.Dirty || info.GoVersion != runtime.Version() {
+		t.Fail()
+		t.Log(n + ": version info incorrectly populated")
+	}
+}
+
+func shouldMarkDirtyVersions(t *testing.T, n string) {
+	if info := cli.NewVersionInfo("v1.2.3-4-gabc123-dirty", "", ""); !info.Dirty {
+		t.Fail()
+		t.Log(n + ": dirty version not detected")
+	}
+}
+
+func shouldPrintNameAndVersionByDefault(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "--version"}
+	var strBuilder strings.Builder
+	runner := cli.NewRunner(cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background())), "v1", &strBuilder)
+	runErr := runner.Run()
+
+	if runErr != nil || strBuilder.String() != "testcmd v1\n" {
+		t.Fail()
+		t.Log(n + ": printed incorrect version: " + strBuilder.String())
+	}
+}
+
+func shouldPrintVersionWithCustomTemplate(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "-v"}
+	var strBuilder strings.Builder
+	runner := cli.NewRunner(cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background())), "", &strBuilder)
+	runner.SetVersionInfo(cli.NewVersionInfo("v2", "abc123", "today"))
+	runner.SetVersionTemplate("{{.Name}} {{.Version}} ({{.Commit}}, {{.BuildDate}})")
+	runErr := runner.Run()
+
+	if runErr != nil || strBuilder.String() != "testcmd v2 (abc123, today)" {
+		t.Fail()
+		t.Log(n + ": printed incorrect version: " + strBuilder.String())
+	}
+}
+
+func shouldPrintVersionAsJSON(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "--version", "--output", "json"}
+	var strBuilder strings.Builder
+	runner := cli.NewRunner(cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background())), "", &strBuilder)
+	runner.SetVersionInfo(cli.NewVersionInfo("v2", "abc123", "today"))
+	runErr := runner.Run()
+	info := &cli.VersionInfo{}
+	jsonErr := json.Unmarshal([]byte(strBuilder.String()), info)
+
+	if runErr != nil || jsonErr != nil || info.Name != "testcmd" || info.Version != "v2" || info.Commit != "abc123" {
+		t.Fail()
+		t.Log(n + ": printed incorrect version JSON: " + strBuilder.String())
+	}
+}
+
+func shouldErrorOnInvalidVersionOutputValue(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "--version", "--output=yaml"}
+	runner := cli.NewRunner(cli.NewParser(cli.GNU, cli.NewCommand("testcmd", context.Background())), "v1", &strings.Builder{})
+
+	if runErr := runner.Run(); runErr == nil {
+		t.Fail()
+		t.Log(n + ": did not error on unsupported output format")
+	}
+}
+
+func shouldBindCommandOutputOption(t *testing.T, n string) {
+	os.Args = []string{"testcmd", "--output", "public"}
+	output := ""
+	cmd := cli.NewCommand("testcmd", context.Background())
+	cmd.AddStringArg(&output, &cli.ArgDefinition{Name: "output", Required: true})
+	cmd.AddRunFunc(func(context.Context, []string) {})
+	var stderr strings.Builder
+	runner := cli.NewRunner(cli.NewParser(cli.GNU, cmd), "v1", &strings.Builder{})
+	runner.SetErrorWriter(&stderr)
+
+	if runErr := runner.Run(); runErr != nil || output != "public" || stderr.String() != "" {
+		t.Fail()
+		t.Log(n + ": command output option collided with version format")
+	}
+}